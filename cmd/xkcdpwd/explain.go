@@ -0,0 +1,101 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+)
+
+// guessRates are the attackers we estimate guessing times for, in guesses per
+// second.
+var guessRates = []struct {
+	name string
+	rate float64
+}{
+	{"online, throttled (100/hour)", 100.0 / 3600},
+	{"online, unthrottled (10/second)", 10},
+	{"offline, slow hash (10 thousand/second)", 1e4},
+	{"offline, fast hash (10 billion/second)", 1e10},
+}
+
+// explain writes a breakdown of the entropy of an n-word passphrase from d,
+// and how long it would take attackers to guess it. Like generating the
+// passphrase, it fails if d has no words or the entropy is too low.
+func explain(w io.Writer, d passphraser, n int) error {
+	if err := d.Check(n); err != nil {
+		return err
+	}
+	entropy := d.PassphraseEntropy(n)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "dictionary size:\t%d words\n", d.Length())
+	fmt.Fprintf(tw, "bits per word:\t%.2f\n", (entropy.Words+entropy.Capitalization)/float64(n))
+	fmt.Fprintf(tw, "words per passphrase:\t%d\n", n)
+	fmt.Fprintf(tw, "entropy:\t%.1f bits\n", entropy.Bits())
+	fmt.Fprintf(tw, "  words:\t%.1f bits\n", entropy.Words)
+	fmt.Fprintf(tw, "  capitalization:\t%.1f bits\n", entropy.Capitalization)
+	fmt.Fprintf(tw, "  separators:\t%.1f bits\n", entropy.Separators)
+	fmt.Fprintf(tw, "  digits:\t%.1f bits\n", entropy.Digits)
+	fmt.Fprintf(tw, "  symbols:\t%.1f bits\n", entropy.Symbols)
+	fmt.Fprintf(tw, "minimum entropy:\t%.1f bits\n", d.MinEntropy())
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "average time to guess:")
+	// on average an attacker has to search half of the possible passphrases
+	guesses := math.Exp2(entropy.Bits() - 1)
+	for _, attacker := range guessRates {
+		fmt.Fprintf(tw, "  %s:\t%s\n", attacker.name, formatDuration(guesses/attacker.rate))
+	}
+	return tw.Flush()
+}
+
+// formatDuration returns seconds as a human readable duration.
+func formatDuration(seconds float64) string {
+	const (
+		minute = 60
+		hour   = 60 * minute
+		day    = 24 * hour
+		year   = 365.25 * day
+	)
+	switch {
+	case math.IsInf(seconds, 1):
+		// the number of guesses overflows above about 1024 bits
+		return fmt.Sprintf("more than %.1e years", math.MaxFloat64/year)
+	case seconds < 1:
+		return "less than a second"
+	case seconds < minute:
+		return formatUnits(seconds, "second")
+	case seconds < hour:
+		return formatUnits(seconds/minute, "minute")
+	case seconds < day:
+		return formatUnits(seconds/hour, "hour")
+	case seconds < year:
+		return formatUnits(seconds/day, "day")
+	case seconds < 1e4*year:
+		return formatUnits(seconds/year, "year")
+	default:
+		return fmt.Sprintf("%.1e years", seconds/year)
+	}
+}
+
+// formatUnits returns n rounded to a whole number of unit, in the plural
+// unless it rounds to 1.
+func formatUnits(n float64, unit string) string {
+	count := fmt.Sprintf("%.0f", n)
+	if count != "1" {
+		unit += "s"
+	}
+	return count + " " + unit
+}
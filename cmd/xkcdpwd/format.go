@@ -0,0 +1,205 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	dict "github.com/wfscheper/xkcdpwd"
+)
+
+// passphraseWriter writes passphrases in an output format.
+type passphraseWriter interface {
+	Write(p dict.Passphrase) error
+	// Flush writes anything buffered, and must be called after the last
+	// passphrase is written.
+	Flush() error
+}
+
+// newPassphraseWriter returns a passphraseWriter to w for format, which is one
+// of csv, json, ndjson or text.
+func newPassphraseWriter(w io.Writer, format string) (passphraseWriter, error) {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"phrase", "words", "wordCount", "entropy", "language"}); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	case "json":
+		return &jsonWriter{w: w}, nil
+	case "ndjson":
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case "text":
+		return &textWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("invalid output format '%s'", format)
+	}
+}
+
+// textWriter writes each passphrase on its own line.
+type textWriter struct {
+	w io.Writer
+}
+
+func (t *textWriter) Write(p dict.Passphrase) error {
+	_, err := fmt.Fprintln(t.w, p.String())
+	return err
+}
+
+func (t *textWriter) Flush() error {
+	return nil
+}
+
+// jsonWriter writes the passphrases as a JSON array.
+type jsonWriter struct {
+	w           io.Writer
+	passphrases []dict.Passphrase
+}
+
+func (j *jsonWriter) Write(p dict.Passphrase) error {
+	j.passphrases = append(j.passphrases, p)
+	return nil
+}
+
+func (j *jsonWriter) Flush() error {
+	enc := json.NewEncoder(j.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(j.passphrases)
+}
+
+// ndjsonWriter writes each passphrase as a JSON object on its own line.
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(p dict.Passphrase) error {
+	return n.enc.Encode(p)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return nil
+}
+
+// csvWriter writes each passphrase as a CSV record, after a header.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(p dict.Passphrase) error {
+	return c.w.Write([]string{
+		p.String(),
+		strings.Join(p.Words, " "),
+		strconv.Itoa(len(p.Words)),
+		strconv.FormatFloat(p.Entropy.Bits(), 'f', -1, 64),
+		p.Language.String(),
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// templateData is what a -template can refer to for each passphrase.
+type templateData struct {
+	Phrase     string   // the passphrase
+	Words      []string // the words of the passphrase
+	WordCount  int      // the number of words
+	Separator  string   // the separator between words, if there is only one
+	Separators []string // the separator of each gap, if chosen for each
+	Index      int      // the index of the passphrase, starting from 0
+	Entropy    float64  // the bits of entropy of the passphrase
+	Language   string   // the language of the words, or und if mixed
+}
+
+// templateWriter executes a template for each passphrase, and ends its output
+// with a new line if the template does not.
+type templateWriter struct {
+	w     io.Writer
+	tmpl  *template.Template
+	index int
+}
+
+func newTemplateWriter(w io.Writer, text string) (*templateWriter, error) {
+	tmpl, err := template.New("passphrase").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return &templateWriter{w: w, tmpl: tmpl}, nil
+}
+
+func (t *templateWriter) Write(p dict.Passphrase) error {
+	var b strings.Builder
+	err := t.tmpl.Execute(&b, templateData{
+		Phrase:     p.String(),
+		Words:      p.Words,
+		WordCount:  len(p.Words),
+		Separator:  p.Separator,
+		Separators: p.Separators,
+		Index:      t.index,
+		Entropy:    p.Entropy.Bits(),
+		Language:   p.Language.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot execute template: %w", err)
+	}
+	t.index++
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+	_, err = io.WriteString(t.w, b.String())
+	return err
+}
+
+func (t *templateWriter) Flush() error {
+	return nil
+}
+
+// isStructured returns whether format is one of the machine readable formats.
+func isStructured(format string) bool {
+	switch format {
+	case "csv", "json", "ndjson":
+		return true
+	}
+	return false
+}
+
+// message is an error, warning or status line for stderr.
+type message struct {
+	Error   string `json:"error,omitempty"`
+	Warning string `json:"warning,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// writeMessage writes m to w, as a JSON object for the machine readable
+// formats, and as a line of text otherwise.
+func writeMessage(w io.Writer, format string, m message) {
+	switch {
+	case isStructured(format):
+		_ = json.NewEncoder(w).Encode(m)
+	case m.Error != "":
+		fmt.Fprintf(w, "error: %s\n", m.Error)
+	case m.Warning != "":
+		fmt.Fprintf(w, "WARNING: %s\n", m.Warning)
+	default:
+		fmt.Fprintln(w, m.Status)
+	}
+}
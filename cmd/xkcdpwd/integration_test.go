@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/wfscheper/xkcdpwd/internal/test"
+	"github.com/wfscheper/xkcdpwd/internal/userinfo"
 )
 
 // Entry point for running integration tests.
@@ -72,10 +73,15 @@ func runTest(name, relPath, wd string, run test.RunFunc) func(t *testing.T) {
 		}
 
 		testEnv := test.NewEnvironment(t, wd, run)
+		testEnv.Setenv(testCase.Env...)
+		cfgfile, err := userinfo.DefaultConfigFile(appName, testEnv.Getenv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCase.InstallConfig(cfgfile)
 
-		var err error
 		for i, args := range testCase.Commands {
-			err = testEnv.Run(args)
+			err = testEnv.Run(args, testCase.Stdin)
 			if err != nil && i < len(testCase.Commands)-1 {
 				t.Fatalf("cmd '%s' raised an unexpected error: %s", strings.Join(args, " "), err.Error())
 			}
@@ -91,8 +97,9 @@ func runTest(name, relPath, wd string, run test.RunFunc) func(t *testing.T) {
 	}
 }
 
-func execCmd(prog string, args []string, stdout, stderr io.Writer, dir string, env []string) error {
+func execCmd(prog string, args []string, stdin io.Reader, stdout, stderr io.Writer, dir string, env []string) error {
 	cmd := exec.Command(prog, args...)
+	cmd.Stdin = stdin
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
 	cmd.Dir = dir
@@ -100,7 +107,7 @@ func execCmd(prog string, args []string, stdout, stderr io.Writer, dir string, e
 	return cmd.Run()
 }
 
-func runMain(prog string, args []string, stdout, stderr io.Writer, dir string, env []string) (err error) {
+func runMain(prog string, args []string, stdin io.Reader, stdout, stderr io.Writer, dir string, env []string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			switch r := r.(type) {
@@ -114,6 +121,7 @@ func runMain(prog string, args []string, stdout, stderr io.Writer, dir string, e
 
 	exc := &Xkcdpwd{
 		Args:       append([]string{prog}, args...),
+		Stdin:      stdin,
 		Stdout:     stdout,
 		Stderr:     stderr,
 		WorkingDir: dir,
@@ -15,18 +15,23 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/pelletier/go-toml"
 	dict "github.com/wfscheper/xkcdpwd"
 	"github.com/wfscheper/xkcdpwd/internal/userinfo"
+	"golang.org/x/text/language"
 )
 
 const (
@@ -50,6 +55,7 @@ func main() {
 
 	exc := &Xkcdpwd{
 		Args:       os.Args,
+		Stdin:      os.Stdin,
 		Stdout:     os.Stdout,
 		Stderr:     os.Stderr,
 		WorkingDir: wd,
@@ -64,6 +70,7 @@ type Xkcdpwd struct {
 	WorkingDir     string    // Where to execute
 	Args           []string  // command-line arguments
 	Env            []string  // os environment
+	Stdin          io.Reader // input reader
 	Stdout, Stderr io.Writer // output writers
 }
 
@@ -73,25 +80,21 @@ func (x *Xkcdpwd) Run() int {
 	outLogger := log.New(x.Stdout, "", 0)
 	errLogger := log.New(x.Stderr, "", 0)
 
-	cfgfileDefault, err := userinfo.DefaultConfigFile(appName)
+	cfgfileDefault, err := userinfo.DefaultConfigFile(appName, x.getenv)
 	if err != nil {
 		errLogger.Printf("Cannot determine default config file location: %s", err)
 		return errorExitCode
 	}
 
-	var cfgfile string
+	// the config file provides the flag defaults, so it has to be found
+	// before the flags are parsed, wherever -cfgfile is among them
 	var cfg *toml.Tree
-	cfgFlags := flag.NewFlagSet(appName, flag.ContinueOnError)
-	cfgFlags.SetOutput(io.Discard)
-	cfgFlags.StringVar(&cfgfile, "cfgfile", "", "path to config file")
-	if err := cfgFlags.Parse(x.Args[1:]); err != nil {
-		switch err {
-		case flag.ErrHelp:
-		default:
-			cfgfile = cfgfileDefault
-		}
+	cfgfileFlag := findFlag(x.Args[1:], "cfgfile")
+	cfgfile := cfgfileFlag
+	if cfgfile == "" {
+		cfgfile = cfgfileDefault
 	}
-	cfg, err = toml.LoadFile(cfgfile)
+	cfg, err = toml.LoadFile(x.path(cfgfile))
 	if err != nil {
 		if !os.IsNotExist(err) {
 			errLogger.Printf("cannot read config file '%s': %s", cfgfile, err)
@@ -105,52 +108,157 @@ func (x *Xkcdpwd) Run() int {
 	// register global flags
 	var (
 		// flags
+		ascii           bool
 		capitalize      string
+		entropy         float64
+		explainEntropy  bool
 		lang            string
 		maxWordLength   int
+		minEntropy      float64
 		minWordLength   int
+		mix             string
+		outputFormat    string
+		padding         dict.Padding
 		passphraseCount int
+		randSource      string
+		seed            string
 		separator       string
+		separatorPolicy string
+		separatorSet    string
 		showVersion     bool
+		templateText    string
+		templateFile    string
 		wordCount       int
+		wordlists       = &listFlag{}
+		wordlistFormat  string
 	)
 	flags := flag.NewFlagSet(appName, flag.ContinueOnError)
 	flags.SetOutput(x.Stderr)
 
 	_ = flags.Bool("v", false, "be more verbose")
-	_ = flags.String("cfgfile", cfgfile, "path to config file")
+	_ = flags.String("cfgfile", cfgfileFlag, "path to config file")
 	flags.BoolVar(&showVersion, "version", false, "show version information")
+	flags.BoolVar(&explainEntropy, "explain", false, "explain the strength of the passphrases instead of generating them")
+
+	var asciiDefault = cfg.GetDefault(appName+".ascii", false).(bool)
+	flags.BoolVar(&ascii, "ascii", asciiDefault, "strip accents from words, and drop words that are not ASCII")
 
 	var capitalizeDefault = cfg.GetDefault(appName+".capitalize", "none").(string)
 	flags.StringVar(&capitalize, "capitalize", capitalizeDefault, "capitalize letters in passphrase")
 
+	var entropyDefault = getFloat(cfg, appName+".entropy", 0)
+	flags.Float64Var(&entropy, "entropy", entropyDefault, "target bits of entropy per passphrase, overrides -words")
+
 	var langDefault = cfg.GetDefault(appName+".lang", "").(string)
 	var langHelpDefault string
 	if langDefault == "" {
 		langHelpDefault = " (default: en)"
 	}
-	flags.StringVar(&lang, "lang", langDefault, fmt.Sprintf("language to use, a valid IETF language tag%s", langHelpDefault))
+	flags.StringVar(&lang, "lang", langDefault, fmt.Sprintf("comma separated languages to use, or the language of the word lists, valid IETF language tags%s", langHelpDefault))
 
 	var maxWordLengthDefault = cfg.GetDefault(appName+".max-length", int64(0)).(int64)
 	flags.IntVar(&maxWordLength, "max-length", int(maxWordLengthDefault), "maximum word length")
 
+	var minEntropyDefault = getFloat(cfg, appName+".min-entropy", dict.DefaultMinEntropy)
+	flags.Float64Var(&minEntropy, "min-entropy", minEntropyDefault, "minimum bits of entropy per passphrase")
+
 	var minWordLengthDefault = cfg.GetDefault(appName+".min-length", int64(0)).(int64)
 	flags.IntVar(&minWordLength, "min-length", int(minWordLengthDefault), "minimum word length")
 
+	var mixDefault = cfg.GetDefault(appName+".mix", "").(string)
+	flags.StringVar(&mix, "mix", mixDefault, "draw each word from a different language or word list: round-robin, random, or a pattern like 1,2,2")
+
+	var outputFormatDefault = cfg.GetDefault(appName+".format", "text").(string)
+	flags.StringVar(&outputFormat, "format", outputFormatDefault, "output format: text, json, ndjson, or csv")
+
+	var padDigitsAfterDefault = cfg.GetDefault(appName+".pad-digits-after", int64(0)).(int64)
+	flags.IntVar(&padding.DigitsAfter, "pad-digits-after", int(padDigitsAfterDefault), "the number of random digits after the words")
+
+	var padDigitsBeforeDefault = cfg.GetDefault(appName+".pad-digits-before", int64(0)).(int64)
+	flags.IntVar(&padding.DigitsBefore, "pad-digits-before", int(padDigitsBeforeDefault), "the number of random digits before the words")
+
+	var padPlacementDefault = cfg.GetDefault(appName+".pad-placement", "around").(string)
+	flags.StringVar(&padding.Placement, "pad-placement", padPlacementDefault, "where to put the padding: around the words, or between them so that passphrases start and end with a word")
+
+	var padSymbolsDefault = cfg.GetDefault(appName+".pad-symbols", dict.DefaultPaddingSymbols).(string)
+	flags.StringVar(&padding.Symbols, "pad-symbols", padSymbolsDefault, "the symbols to pad with")
+
+	var padSymbolsAfterDefault = cfg.GetDefault(appName+".pad-symbols-after", int64(0)).(int64)
+	flags.IntVar(&padding.SymbolsAfter, "pad-symbols-after", int(padSymbolsAfterDefault), "the number of random symbols after the words and digits")
+
+	var padSymbolsBeforeDefault = cfg.GetDefault(appName+".pad-symbols-before", int64(0)).(int64)
+	flags.IntVar(&padding.SymbolsBefore, "pad-symbols-before", int(padSymbolsBeforeDefault), "the number of random symbols before the words and digits")
+
 	var passphraseCountDefault = cfg.GetDefault(appName+".phrases", int64(10)).(int64)
 	flags.IntVar(&passphraseCount, "phrases", int(passphraseCountDefault), "the number of passphrases")
 
+	var randSourceDefault = cfg.GetDefault(appName+".rand-source", "").(string)
+	flags.StringVar(&randSource, "rand-source", randSourceDefault, "path to a file or device to read random bytes from, instead of the system's random number generator")
+
+	// seed is for reproducible test output, so it is left out of the usage
+	flags.StringVar(&seed, "seed", "", "generate predictable passphrases from a seed, for testing only")
+
 	var separatorDefault = cfg.GetDefault(appName+".separator", " ").(string)
 	flags.StringVar(&separator, "separator", separatorDefault, "passphrase separator")
 
+	var templateDefault = cfg.GetDefault(appName+".template", "").(string)
+	flags.StringVar(&templateText, "template", templateDefault, "Go template to print each passphrase with, such as '{{.Phrase}}'")
+
+	var templateFileDefault = cfg.GetDefault(appName+".template-file", "").(string)
+	flags.StringVar(&templateFile, "template-file", templateFileDefault, "path to a file with a Go template to print each passphrase with")
+
+	var separatorPolicyDefault = cfg.GetDefault(appName+".separator-policy", "gap").(string)
+	flags.StringVar(&separatorPolicy, "separator-policy", separatorPolicyDefault, "how often to choose a separator from -separator-set: gap, for each gap between words, or phrase")
+
+	var separatorSetDefault = cfg.GetDefault(appName+".separator-set", "").(string)
+	flags.StringVar(&separatorSet, "separator-set", separatorSetDefault, "characters to choose separators from at random, instead of using -separator")
+
+	wordlists.values = getStrings(cfg, appName+".wordlist")
+	flags.Var(wordlists, "wordlist", "path to a word list, or - for stdin, may be repeated")
+
+	var wordlistFormatDefault = cfg.GetDefault(appName+".wordlist-format", "auto").(string)
+	flags.StringVar(&wordlistFormat, "wordlist-format", wordlistFormatDefault, "format of word lists: auto, plain, or diceware")
+
 	var wordCountDefault = cfg.GetDefault(appName+".words", int64(4)).(int64)
 	flags.IntVar(&wordCount, "words", int(wordCountDefault), "the number of words in each passphrase")
 
+	// parse errors are reported in the output format, which is only known
+	// once the flags are parsed, so the flag package must not print them
 	setUsage(errLogger, flags)
-	if err := flags.Parse(x.Args[1:]); err != nil {
+	usage := flags.Usage
+	flags.Usage = func() {}
+	flags.SetOutput(io.Discard)
+	parse := func(args []string) bool {
+		err := flags.Parse(args)
+		switch {
+		case err == nil:
+			return true
+		case errors.Is(err, flag.ErrHelp):
+			usage()
+		case isStructured(outputFormat):
+			writeMessage(x.Stderr, outputFormat, message{Error: err.Error()})
+		default:
+			errLogger.Println(err)
+			usage()
+		}
+		return false
+	}
+	if !parse(x.Args[1:]) {
+		return errorExitCode
+	}
+
+	// the dice command reads its random choices from dice rolls on stdin. It
+	// stops the parsing of the flags before it, so parse the flags after it.
+	diceMode := flags.Arg(0) == "dice"
+	if diceMode && !parse(flags.Args()[1:]) {
 		return errorExitCode
 	}
 
+	// rolling dice is slow, so only roll one passphrase unless asked for more
+	if diceMode && !isFlagSet(flags, "phrases") {
+		passphraseCount = 1
+	}
+
 	if showVersion {
 		outLogger.Printf(`%s
  version     : %s
@@ -163,51 +271,510 @@ func (x *Xkcdpwd) Run() int {
 		return successExitCode
 	}
 
+	// check that format is valid, so that errors can be reported in it
+	out, err := newPassphraseWriter(x.Stdout, outputFormat)
+	if err != nil {
+		errLogger.Printf("error: %v\n", err)
+		return errorExitCode
+	}
+	fail := func(err error) int {
+		writeMessage(x.Stderr, outputFormat, message{Error: err.Error()})
+		return errorExitCode
+	}
+
+	// anything left over would be silently ignored, such as a misplaced dice
+	if flags.NArg() > 0 {
+		return fail(fmt.Errorf("unexpected argument '%s'", flags.Arg(0)))
+	}
+
+	// a template replaces the text format
+	if templateText != "" || templateFile != "" {
+		switch {
+		case templateText != "" && templateFile != "":
+			return fail(errors.New("cannot use both a template and a template file"))
+		case outputFormat != "text":
+			return fail(fmt.Errorf("cannot use a template with the %s format", outputFormat))
+		}
+		if templateFile != "" {
+			b, err := os.ReadFile(x.path(templateFile))
+			if err != nil {
+				return fail(fmt.Errorf("cannot read template file '%s': %v", templateFile, errors.Unwrap(err)))
+			}
+			templateText = string(b)
+		}
+		if out, err = newTemplateWriter(x.Stdout, templateText); err != nil {
+			return fail(err)
+		}
+	}
+
+	// the explanation is only written as text
+	if explainEntropy {
+		switch {
+		case outputFormat != "text":
+			return fail(fmt.Errorf("cannot explain passphrases in the %s format", outputFormat))
+		case templateText != "":
+			return fail(errors.New("cannot explain passphrases with a template"))
+		}
+	}
+
 	// check that words is valid
 	if wordCount <= 0 {
-		errLogger.Printf("error: words must be greater than 0")
-		return errorExitCode
+		return fail(errors.New("words must be greater than 0"))
+	}
+
+	// check that entropy is valid
+	if math.IsNaN(entropy) || math.IsInf(entropy, 0) {
+		return fail(errors.New("entropy must be a finite number"))
+	}
+	if entropy < 0 {
+		return fail(errors.New("entropy must not be negative"))
+	}
+
+	// check that min-entropy is valid
+	if math.IsNaN(minEntropy) {
+		return fail(errors.New("min-entropy must be a number"))
+	}
+	if minEntropy < 0 {
+		return fail(errors.New("min-entropy must not be negative"))
+	}
+
+	// check that padding is valid
+	if padding.DigitsBefore < 0 || padding.DigitsAfter < 0 || padding.SymbolsBefore < 0 || padding.SymbolsAfter < 0 {
+		return fail(errors.New("padding must not be negative"))
+	}
+	switch padding.Placement {
+	case "around", "between":
+	default:
+		return fail(fmt.Errorf("invalid padding placement '%s'", padding.Placement))
+	}
+	if padding.Symbols == "" && (padding.SymbolsBefore > 0 || padding.SymbolsAfter > 0) {
+		return fail(errors.New("pad-symbols must not be empty"))
 	}
 
 	// check that phrases is valid
 	if passphraseCount <= 0 {
-		errLogger.Printf("error: phrases must be greater than 0")
-		return errorExitCode
+		return fail(errors.New("phrases must be greater than 0"))
 	}
 
 	// check that separator is valid
 	if !checkSeparator(separator) {
-		errLogger.Printf("error: invalid separator '%s'\n", separator)
-		return errorExitCode
+		return fail(fmt.Errorf("invalid separator '%s'", separator))
+	}
+
+	// check that separator-set is valid; a separator and a separator set
+	// only conflict when given at the same level, and either one on the
+	// command line overrides the other in the config file
+	separatorFlag, separatorSetFlag := isFlagSet(flags, "separator"), isFlagSet(flags, "separator-set")
+	switch {
+	case separatorFlag && separatorSetFlag && separatorSet != "",
+		!separatorFlag && !separatorSetFlag && separatorSet != "" && cfg.Has(appName+".separator"):
+		return fail(errors.New("cannot use both a separator and a separator set"))
+	case separatorFlag && !separatorSetFlag:
+		separatorSet = ""
+	}
+	switch separatorPolicy {
+	case "gap", "phrase":
+	default:
+		return fail(fmt.Errorf("invalid separator policy '%s'", separatorPolicy))
 	}
 
 	// check that capitalize is valid
 	switch capitalize {
 	case "all", "first", "none", "random":
 	default:
-		errLogger.Printf("error: invalid capitalization strategy '%s'", capitalize)
-		return errorExitCode
+		return fail(fmt.Errorf("invalid capitalization strategy '%s'", capitalize))
+	}
+
+	// check that wordlist-format is valid
+	switch wordlistFormat {
+	case "auto", "diceware", "plain":
+	default:
+		return fail(fmt.Errorf("invalid word list format '%s'", wordlistFormat))
 	}
 
-	// Source lang from the environment, but prefer the command line if set
-	if envLang, ok := os.LookupEnv("LANG"); ok && lang == "" {
-		lang = envLang
+	// check that only one random source is chosen
+	switch {
+	case diceMode && seed != "":
+		return fail(errors.New("cannot use a seed when rolling dice"))
+	case diceMode && randSource != "":
+		return fail(errors.New("cannot use a random source when rolling dice"))
+	case seed != "" && randSource != "":
+		return fail(errors.New("cannot use both a seed and a random source"))
+	}
+	if seed != "" {
+		writeMessage(x.Stderr, outputFormat, message{Warning: "passphrases generated from -seed are predictable, never use them as real secrets"})
+	}
+
+	// stdin can only be read once, so only one word list can come from it
+	var stdinWordlists int
+	for _, path := range wordlists.values {
+		if path == "-" {
+			stdinWordlists++
+		}
+	}
+	if stdinWordlists > 1 {
+		return fail(errors.New("stdin can only be used for one word list"))
+	}
+
+	// check that stdin is not needed for both dice rolls and a word list
+	if diceMode {
+		for _, path := range wordlists.values {
+			if path == "-" {
+				return fail(errors.New("cannot read a word list from stdin when rolling dice"))
+			}
+		}
+	}
+
+	dicts, err := x.loadDictionaries(lang, wordlists.values, wordlistFormat)
+	if err != nil {
+		return fail(err)
+	}
+	opts := []dict.Option{
+		dict.WithASCII(ascii),
+		dict.WithCapitalize(capitalize),
+		dict.WithLengthRange(minWordLength, maxWordLength),
+		dict.WithMinEntropy(minEntropy),
+		dict.WithPadding(padding),
+		dict.WithSeparator(separator),
+		dict.WithSeparatorSet(separatorSet, separatorPolicy),
+	}
+	var d passphraser
+	if mix == "" {
+		merged := dicts[0]
+		if len(dicts) > 1 {
+			merged = dict.Merge(dicts...)
+		}
+		d = merged.With(opts...)
+	} else {
+		for i, source := range dicts {
+			dicts[i] = source.With(opts...)
+		}
+		m, err := newMultiDictionary(mix, dicts)
+		if err != nil {
+			return fail(err)
+		}
+		m.SetMinEntropy(minEntropy)
+		m.SetPadding(padding)
+		m.SetSeparator(separator)
+		m.SetSeparatorSet(separatorSet)
+		m.SetSeparatorPolicy(separatorPolicy)
+		d = m
+	}
+	if entropy > 0 {
+		wordCount, err = d.WordsForEntropy(entropy)
+		if err != nil {
+			return fail(err)
+		}
+	}
+	if explainEntropy {
+		if err := explain(x.Stdout, d, wordCount); err != nil {
+			return fail(err)
+		}
+		return successExitCode
+	}
+	if seed != "" {
+		d.SetRandSource(dict.NewSeededReader([]byte(seed)))
+	}
+	if randSource != "" {
+		f, err := os.Open(x.path(randSource))
+		if err != nil {
+			return fail(fmt.Errorf("cannot open random source '%s': %v", randSource, errors.Unwrap(err)))
+		}
+		defer f.Close()
+		d.SetRandSource(f)
+	}
+	var rolls *dict.DiceReader
+	if diceMode {
+		rolls = dict.NewDiceReader(x.Stdin)
+		d.SetRandSource(rolls)
+		if isTerminal(x.Stdin) {
+			bits := float64(passphraseCount) * d.PassphraseEntropy(wordCount).Bits()
+			// allow for rounding error when the passphrase entropy is an
+			// exact number of rolls
+			needed := math.Ceil(bits/math.Log2(6) - 1e-9)
+			writeMessage(x.Stderr, outputFormat, message{
+				Status: fmt.Sprintf("enter at least %.0f dice rolls, from 1 to 6, separated by spaces or new lines:", needed),
+			})
+		}
 	}
-	d := dict.GetDict(lang)
-	d.SetCapitalize(capitalize)
-	d.SetMaxWordLength(maxWordLength)
-	d.SetMinWordLength(minWordLength)
 	for i := 0; i < passphraseCount; i++ {
-		words, err := d.Passphrase(wordCount)
+		p, err := d.Generate(wordCount)
 		if err != nil {
-			errLogger.Printf("error: %v\n", err)
-			return errorExitCode
+			if randSource != "" && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) {
+				err = fmt.Errorf("random source '%s' ran out of bytes", randSource)
+			}
+			return fail(err)
+		}
+		if err := out.Write(p); err != nil {
+			return fail(err)
 		}
-		outLogger.Println(strings.Join(words, separator))
+	}
+	if err := out.Flush(); err != nil {
+		return fail(err)
+	}
+	if diceMode {
+		writeMessage(x.Stderr, outputFormat, message{
+			Status: fmt.Sprintf("used %d dice rolls with %.1f bits of entropy, each passphrase has %.1f bits of entropy",
+				rolls.Rolls(), rolls.Entropy(), d.PassphraseEntropy(wordCount).Bits()),
+		})
 	}
 	return successExitCode
 }
 
+// passphraser generates passphrases from either a Dictionary or a
+// MultiDictionary.
+type passphraser interface {
+	Check(n int) error
+	Generate(n int) (dict.Passphrase, error)
+	Length() int
+	MinEntropy() float64
+	PassphraseEntropy(n int) dict.Entropy
+	SetRandSource(r io.Reader)
+	WordsForEntropy(bits float64) (int, error)
+}
+
+// loadDictionaries returns a dictionary for each of the word lists at paths,
+// read in format and capitalized with the rules of the language lang. Without
+// paths, it returns a dictionary for each of the word lists of the comma
+// separated languages in lang, or for the language of the environment if lang
+// is empty. Those word lists are found in the environment of x.
+func (x *Xkcdpwd) loadDictionaries(lang string, paths []string, format string) ([]*dict.Dictionary, error) {
+	if len(paths) > 0 {
+		var tag language.Tag
+		if lang != "" {
+			if strings.Contains(lang, ",") {
+				return nil, fmt.Errorf("word lists can only have one language, got '%s'", lang)
+			}
+			var err error
+			if tag, err = language.Parse(lang); err != nil {
+				return nil, fmt.Errorf("invalid language '%s': %w", lang, err)
+			}
+		}
+		dicts := make([]*dict.Dictionary, 0, len(paths))
+		for _, path := range paths {
+			d, err := x.readWordlist(path, format)
+			if err != nil {
+				return nil, err
+			}
+			if lang != "" {
+				d.SetLanguage(tag)
+			}
+			dicts = append(dicts, d)
+		}
+		return dicts, nil
+	}
+
+	r, err := dict.NewRegistry(x.getenv)
+	if err != nil {
+		return nil, err
+	}
+	if lang == "" {
+		d, err := loadEnvLanguage(r, x.getenv("LANG"))
+		if err != nil {
+			return nil, err
+		}
+		return []*dict.Dictionary{d}, nil
+	}
+	var dicts []*dict.Dictionary
+	for _, l := range strings.Split(lang, ",") {
+		// an empty language would load the default language again
+		l = strings.TrimSpace(l)
+		if l == "" {
+			return nil, fmt.Errorf("empty language in '%s'", lang)
+		}
+		d, err := r.LoadDictionary(l)
+		if err != nil {
+			return nil, withLanguages(r, err)
+		}
+		dicts = append(dicts, d)
+	}
+	return dicts, nil
+}
+
+// newMultiDictionary returns a MultiDictionary of dicts using the policy mix,
+// which is either the name of a policy or a comma separated pattern of
+// 1-based dictionary positions.
+func newMultiDictionary(mix string, dicts []*dict.Dictionary) (*dict.MultiDictionary, error) {
+	m := dict.NewMultiDictionary(dicts...)
+	switch mix {
+	case "round-robin", "random":
+		m.SetPolicy(mix)
+		return m, nil
+	}
+	var pattern []int
+	for _, field := range strings.Split(mix, ",") {
+		position, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid mix '%s'", mix)
+		}
+		if position < 1 || position > len(dicts) {
+			return nil, fmt.Errorf("mix '%s' refers to word list %d, but there are only %d", mix, position, len(dicts))
+		}
+		pattern = append(pattern, position-1)
+	}
+	if err := m.SetPattern(pattern...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// loadEnvLanguage returns the dictionary of r for the locale env, or for the
+// default language if it is not supported.
+func loadEnvLanguage(r *dict.Registry, env string) (*dict.Dictionary, error) {
+	d, err := r.LoadDictionary(env)
+	if errors.Is(err, dict.ErrUnknownLanguage) {
+		// an unsupported locale should not stop us from generating passphrases
+		return r.LoadDictionary("")
+	}
+	return d, err
+}
+
+// withLanguages adds the languages of r to err if it is about an unsupported
+// language.
+func withLanguages(r *dict.Registry, err error) error {
+	if !errors.Is(err, dict.ErrUnknownLanguage) {
+		return err
+	}
+	tags := r.Languages()
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.String())
+	}
+	return fmt.Errorf("%w, supported languages are %s", err, strings.Join(names, ", "))
+}
+
+// readWordlist returns a dictionary of the words in the file at path, or
+// from stdin if path is "-", read in format.
+func (x *Xkcdpwd) readWordlist(path, format string) (*dict.Dictionary, error) {
+	var r io.Reader
+	if path == "-" {
+		r = x.Stdin
+	} else {
+		f, err := os.Open(x.path(path))
+		if err != nil {
+			// report the path as the user gave it
+			return nil, fmt.Errorf("cannot open word list '%s': %w", path, errors.Unwrap(err))
+		}
+		defer f.Close()
+		r = f
+	}
+	return dict.ReadDictionaryFormat(r, format)
+}
+
+// getenv returns the value of the environment variable key in x.Env, or an
+// empty string if it is not set.
+func (x *Xkcdpwd) getenv(key string) string {
+	for i := len(x.Env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(x.Env[i], "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// path returns path relative to the working directory.
+func (x *Xkcdpwd) path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(x.WorkingDir, path)
+}
+
+// isFlagSet returns whether the flag name was set on the command line.
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// isTerminal returns whether r is an interactive terminal.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// findFlag returns the value of the flag name in args, or an empty string if
+// it is not set. Like the flag package, it stops at "--", but it looks past
+// other arguments, such as the dice command.
+func findFlag(args []string, name string) string {
+	value := ""
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		arg = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		switch {
+		case arg == name && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, name+"="):
+			value = strings.TrimPrefix(arg, name+"=")
+		}
+	}
+	return value
+}
+
+// listFlag is a flag that can be repeated to build a list of values. Values
+// given on the command line replace the default values from the config file.
+type listFlag struct {
+	values []string
+	set    bool
+}
+
+func (l *listFlag) String() string {
+	return strings.Join(l.values, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	if !l.set {
+		l.values = nil
+		l.set = true
+	}
+	l.values = append(l.values, value)
+	return nil
+}
+
+// getStrings returns the string or array of strings at key in cfg.
+func getStrings(cfg *toml.Tree, key string) []string {
+	switch v := cfg.Get(key).(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// getFloat returns the number at key in cfg, or def if key is not set. TOML
+// distinguishes integers from floats, but users should not have to.
+func getFloat(cfg *toml.Tree, key string, def float64) float64 {
+	switch v := cfg.GetDefault(key, def).(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	default:
+		return def
+	}
+}
+
 func checkSeparator(sep string) bool {
 	switch sep {
 	case "", " ", ".", "-", "_", "=":
@@ -217,10 +784,16 @@ func checkSeparator(sep string) bool {
 	}
 }
 
+// hiddenFlags are left out of the usage.
+var hiddenFlags = map[string]bool{"seed": true}
+
 func setUsage(logger *log.Logger, fs *flag.FlagSet) {
 	var flagsUsage bytes.Buffer
 	tw := tabwriter.NewWriter(&flagsUsage, 0, 4, 2, ' ', 0)
 	fs.VisitAll(func(f *flag.Flag) {
+		if hiddenFlags[f.Name] {
+			return
+		}
 		switch f.DefValue {
 		case "":
 			fmt.Fprintf(tw, "\t-%s\t%s\n", f.Name, f.Usage)
@@ -232,10 +805,14 @@ func setUsage(logger *log.Logger, fs *flag.FlagSet) {
 	})
 	tw.Flush()
 	fs.Usage = func() {
-		logger.Printf("Usage: %s [OPTIONS]\n", appName)
+		logger.Printf("Usage: %s [dice] [OPTIONS]\n", appName)
 		logger.Println()
 		logger.Printf("%s is a passphrase generator based on XKCD comic #936\n", appName)
 		logger.Println()
+		logger.Println("Commands:")
+		logger.Println()
+		logger.Println("  dice  generate passphrases from dice rolls read from stdin")
+		logger.Println()
 		logger.Println("Flags:")
 		logger.Println()
 		logger.Println(flagsUsage.String())
@@ -13,7 +13,15 @@
 
 package main
 
-import "testing"
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wfscheper/xkcdpwd/internal/userinfo"
+)
 
 func Test_checkSeparatro(t *testing.T) {
 	// valid
@@ -30,3 +38,96 @@ func Test_checkSeparatro(t *testing.T) {
 		}
 	}
 }
+
+func Test_formatDuration(t *testing.T) {
+	tests := []struct {
+		seconds  float64
+		expected string
+	}{
+		{0, "less than a second"},
+		{0.5, "less than a second"},
+		{1, "1 second"},
+		{1.4, "1 second"},
+		{59, "59 seconds"},
+		{60, "1 minute"},
+		{3600, "1 hour"},
+		{86400, "1 day"},
+		{365.25 * 86400, "1 year"},
+		{7200, "2 hours"},
+		{3 * 86400, "3 days"},
+		{2 * 365.25 * 86400, "2 years"},
+		{1e13 * 365.25 * 86400, "1.0e+13 years"},
+		{math.Inf(1), "more than 5.7e+300 years"},
+	}
+	for _, test := range tests {
+		if actual := formatDuration(test.seconds); actual != test.expected {
+			t.Errorf("expected '%s', got '%s'", test.expected, actual)
+		}
+	}
+}
+
+func Test_writeMessage(t *testing.T) {
+	tests := []struct {
+		format   string
+		m        message
+		expected string
+	}{
+		{"text", message{Error: "boom"}, "error: boom\n"},
+		{"text", message{Warning: "careful"}, "WARNING: careful\n"},
+		{"text", message{Status: "used 3 dice rolls"}, "used 3 dice rolls\n"},
+		{"json", message{Error: "boom"}, `{"error":"boom"}` + "\n"},
+		{"ndjson", message{Status: "used 3 dice rolls"}, `{"status":"used 3 dice rolls"}` + "\n"},
+		{"csv", message{Warning: "careful"}, `{"warning":"careful"}` + "\n"},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		writeMessage(&buf, test.format, test.m)
+		if actual := buf.String(); actual != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", test.format, test.expected, actual)
+		}
+	}
+}
+
+func Test_loadDictionaries(t *testing.T) {
+	// the user languages directory is found in the environment of the
+	// command, not that of the process
+	home := t.TempDir()
+	x := &Xkcdpwd{Env: []string{"HOME=" + home, "XDG_CONFIG_HOME=" + home, "AppData=" + home}}
+	dir, err := userinfo.DefaultLanguagesDir(appName, x.getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "la"), []byte("unus\nduo\ntres\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dicts, err := x.loadDictionaries("la", nil, "auto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := dicts[0].Length(); n != 3 {
+		t.Errorf("expected 3 words, got %d", n)
+	}
+}
+
+func Test_findFlag(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{}, ""},
+		{[]string{"-cfgfile", "a.conf"}, "a.conf"},
+		{[]string{"-words", "4", "--cfgfile", "a.conf"}, "a.conf"},
+		{[]string{"dice", "-cfgfile=a.conf"}, "a.conf"},
+		{[]string{"-cfgfile", "a.conf", "-cfgfile", "b.conf"}, "b.conf"},
+		{[]string{"--", "-cfgfile", "a.conf"}, ""},
+		{[]string{"-cfgfile"}, ""},
+	}
+	for _, test := range tests {
+		if actual := findFlag(test.args, "cfgfile"); actual != test.expected {
+			t.Errorf("%v: expected '%s', got '%s'", test.args, test.expected, actual)
+		}
+	}
+}
@@ -34,14 +34,22 @@ func TestMain(m *testing.M) {
 		// those systems.  Set CCACHE_DIR to cope.  Issue 17668.
 		os.Setenv("CCACHE_DIR", filepath.Join(home, ".ccache"))
 	}
-	os.Setenv("HOME", "/test-home-does-not-exist")
+	// Don't read the config file or word lists of the user running the tests.
+	home, err := os.MkdirTemp("", "testxkcdpwd")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "creating home directory failed: %v\n", err)
+		os.Exit(2)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
 	if os.Getenv("GOCACHE") == "" {
-		os.Setenv("GOCACHE", "off") // because $HOME is gone
+		os.Setenv("GOCACHE", "off") // because $HOME is empty
 	}
 
 	r := m.Run()
 
 	os.Remove("testxkcdpwd" + test.ExeSuffix)
+	os.RemoveAll(home)
 
 	os.Exit(r)
 }
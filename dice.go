@@ -0,0 +1,134 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	// ErrInvalidRoll is returned when dice rolls contain anything other than
+	// the numbers 1 to 6.
+	ErrInvalidRoll = errors.New("invalid dice roll")
+	// ErrNotEnoughRolls is returned when the dice rolls run out before a
+	// passphrase is finished.
+	ErrNotEnoughRolls = errors.New("not enough dice rolls")
+)
+
+// DiceReader reads rolls of a six-sided die, written as the digits 1 to 6 and
+// optionally separated by white space, such as "16655 12345". Used as the
+// random source of a Dictionary, it lets physical dice choose the words of a
+// passphrase instead of the machine's random number generator.
+//
+// Read turns the rolls into unbiased random bits: a roll of 1 to 4 gives two
+// bits, and a roll of 5 or 6 gives one bit. When a Dictionary reads a complete
+// Diceware word list, it instead looks each word up by its rolls, as if done
+// by hand.
+//
+// A DiceReader is safe for concurrent use, though which goroutine gets which
+// rolls is then up to chance.
+type DiceReader struct {
+	mu    sync.Mutex
+	r     *bufio.Reader
+	bits  uint
+	nbits int
+	rolls int
+}
+
+// NewDiceReader returns a DiceReader of the rolls in r.
+func NewDiceReader(r io.Reader) *DiceReader {
+	return &DiceReader{r: bufio.NewReader(r)}
+}
+
+// Rolls returns the number of rolls read so far.
+func (d *DiceReader) Rolls() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.rolls
+}
+
+// Entropy returns the bits of entropy of the rolls read so far. Turning rolls
+// into bits wastes some of their entropy, so this can be more than the entropy
+// of the passphrases they generated.
+func (d *DiceReader) Entropy() float64 {
+	return float64(d.Rolls()) * math.Log2(6)
+}
+
+// Read fills p with random bits from the rolls. It returns an error wrapping
+// ErrInvalidRoll if it reads something other than a roll, or
+// ErrNotEnoughRolls if the rolls run out before p is full.
+func (d *DiceReader) Read(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := range p {
+		for d.nbits < 8 {
+			roll, err := d.roll()
+			if err != nil {
+				return i, err
+			}
+			if roll <= 4 {
+				d.bits = d.bits<<2 | uint(roll-1)
+				d.nbits += 2
+			} else {
+				d.bits = d.bits<<1 | uint(roll-5)
+				d.nbits++
+			}
+		}
+		d.nbits -= 8
+		p[i] = byte(d.bits >> d.nbits)
+		d.bits &= 1<<d.nbits - 1
+	}
+	return len(p), nil
+}
+
+// Roll returns the next n rolls, such as "16655".
+func (d *DiceReader) Roll(n int) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		roll, err := d.roll()
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + roll))
+	}
+	return b.String(), nil
+}
+
+// roll returns the next roll, skipping white space.
+func (d *DiceReader) roll() (int, error) {
+	for {
+		r, _, err := d.r.ReadRune()
+		switch {
+		case errors.Is(err, io.EOF):
+			return 0, ErrNotEnoughRolls
+		case err != nil:
+			return 0, err
+		case unicode.IsSpace(r):
+			continue
+		case r < '1' || r > '6':
+			return 0, fmt.Errorf("%w '%c', rolls must be 1 to 6", ErrInvalidRoll, r)
+		}
+		d.rolls++
+		return int(r - '0'), nil
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDiceReader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rolls         string
+		expected      []byte
+		expectedErr   error
+		expectedRolls int
+	}{
+		// 1 to 4 are two bits each, 00 01 10 11
+		{"1234", []byte{0x1b}, nil, 4},
+		{"1 2\n3\t4", []byte{0x1b}, nil, 4},
+		// 5 and 6 are one bit each
+		{"56565656", []byte{0x55}, nil, 8},
+		{"4 5 6 1 1", []byte{0xd0}, nil, 5},
+		{"4443", []byte{0xfe}, nil, 4},
+		{"123", []byte{}, ErrNotEnoughRolls, 3},
+		{"12x4", []byte{}, ErrInvalidRoll, 2},
+		{"1207", []byte{}, ErrInvalidRoll, 2},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			r := NewDiceReader(strings.NewReader(test.rolls))
+			p := make([]byte, 1)
+			n, err := r.Read(p)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if !bytes.Equal(p[:n], test.expected) {
+				t.Errorf("expected %x, got %x", test.expected, p[:n])
+			}
+			if r.Rolls() != test.expectedRolls {
+				t.Errorf("expected %d rolls, got %d", test.expectedRolls, r.Rolls())
+			}
+		})
+	}
+}
+
+func TestDiceReaderRoll(t *testing.T) {
+	t.Parallel()
+	r := NewDiceReader(strings.NewReader("16 6\n2"))
+	roll, err := r.Roll(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roll != "166" {
+		t.Errorf("expected 166, got %s", roll)
+	}
+	if _, err := r.Roll(2); !errors.Is(err, ErrNotEnoughRolls) {
+		t.Errorf("expected error %v, got %v", ErrNotEnoughRolls, err)
+	}
+}
+
+func TestDiceReaderUniform(t *testing.T) {
+	t.Parallel()
+	// simulate fair dice rolls by rejecting the values 6 and 7 of 3 random
+	// bits
+	var rolls strings.Builder
+	h := &hashReader{}
+	buf := make([]byte, 1)
+	for rolls.Len() < 200000 {
+		if _, err := h.Read(buf); err != nil {
+			t.Fatal(err)
+		}
+		if v := buf[0] & 7; v < 6 {
+			rolls.WriteByte('1' + v)
+		}
+	}
+	r := NewDiceReader(strings.NewReader(rolls.String()))
+	observed := make([]int, 256)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			break
+		}
+		observed[buf[n-1]]++
+	}
+	assertUniform(t, observed)
+}
+
+func TestDicePassphrase(t *testing.T) {
+	t.Parallel()
+	// words of a complete Diceware word list are looked up by their rolls
+	d := NewDictionary(bytes.NewBufferString(dicewareList(2)))
+	d.SetMinEntropy(0)
+	r := NewDiceReader(strings.NewReader("35 11 66"))
+	d.SetRandSource(r)
+	words, err := d.Passphrase(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := strings.Join(words, " "); actual != "w35 w11 w66" {
+		t.Errorf("expected w35 w11 w66, got %s", actual)
+	}
+	if r.Rolls() != 6 {
+		t.Errorf("expected 6 rolls, got %d", r.Rolls())
+	}
+
+	// otherwise the rolls are turned into random bits
+	d.SetASCII(true)
+	d.SetRandSource(NewDiceReader(strings.NewReader(strings.Repeat("1234", 3))))
+	words, err = d.Passphrase(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 3 {
+		t.Errorf("expected 3 words, got %v", words)
+	}
+	if _, err := d.Passphrase(1); !errors.Is(err, ErrNotEnoughRolls) {
+		t.Errorf("expected error %v, got %v", ErrNotEnoughRolls, err)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// isDiceware returns whether lines look like a Diceware word list, which is
+// when any of them starts with a dice roll followed by a word. A list where
+// only some lines do is a malformed Diceware word list, rather than a plain
+// word list with numbers in its words.
+func isDiceware(lines []string) bool {
+	for _, line := range lines {
+		if _, _, ok := cutRoll(line); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cutRoll returns the dice roll and the rest of line, with any trailing
+// comment removed, if line starts with a dice roll followed by a word.
+func cutRoll(line string) (string, string, bool) {
+	line = stripComment(line)
+	i := strings.IndexFunc(line, unicode.IsSpace)
+	if i < 0 || !isRoll(line[:i]) {
+		return "", "", false
+	}
+	return line[:i], strings.TrimSpace(line[i:]), true
+}
+
+// isRoll returns whether s is a roll of one or more six-sided dice.
+func isRoll(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '1' || r > '6' {
+			return false
+		}
+	}
+	return true
+}
+
+// readDiceware returns a Dictionary of a Diceware word list, where each line
+// is a dice roll followed by a word. Every roll must use the same number of
+// dice, and every possible roll must appear exactly once, so that each word
+// is equally likely to be rolled. If lines are not a valid Diceware word list,
+// then an error wrapping ErrInvalidDiceware is returned, along with a
+// Dictionary without a dice index. Its words are those of the Diceware word
+// list if every line has a roll and a word, and the lines read as a plain
+// word list, without their rolls, otherwise. As in plain word lists, trailing
+// comments are ignored.
+func readDiceware(lines []string) (*Dictionary, error) {
+	rollList := make([]string, 0, len(lines))
+	wordList := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if stripComment(line) == "" {
+			continue
+		}
+		roll, word, ok := cutRoll(line)
+		if !ok || strings.IndexFunc(word, unicode.IsSpace) >= 0 {
+			return fromWords(plainWords(withoutRolls(lines))), fmt.Errorf("%w: '%s' is not a dice roll followed by a word", ErrInvalidDiceware, line)
+		}
+		rollList = append(rollList, roll)
+		wordList = append(wordList, norm.NFC.String(word))
+	}
+	if len(rollList) == 0 {
+		return fromWords(nil), nil
+	}
+
+	dice := len(rollList[0])
+	words := make([]string, 0, len(lines))
+	rolls := make(map[string]string, len(lines))
+	wordsByRoll := make(map[string]string, len(lines))
+	var err error
+	for i, roll := range rollList {
+		word := wordList[i]
+		_, repeatedRoll := wordsByRoll[roll]
+		_, repeatedWord := rolls[word]
+		switch {
+		case err != nil:
+		case len(roll) != dice:
+			err = fmt.Errorf("%w: roll %s has %d dice, expected %d", ErrInvalidDiceware, roll, len(roll), dice)
+		case repeatedRoll:
+			err = fmt.Errorf("%w: roll %s is repeated", ErrInvalidDiceware, roll)
+		case repeatedWord:
+			err = fmt.Errorf("%w: word '%s' is repeated", ErrInvalidDiceware, word)
+		}
+		if repeatedWord {
+			continue
+		}
+		words = append(words, word)
+		rolls[word] = roll
+		wordsByRoll[roll] = word
+	}
+	if err == nil {
+		// with no repeated rolls, there must be 6^dice words for every roll
+		// to be covered
+		expected := 1
+		for i := 0; i < dice && expected <= len(words); i++ {
+			expected *= 6
+		}
+		if expected != len(words) {
+			err = fmt.Errorf("%w: %d words, but rolls of %d dice need 6^%d", ErrInvalidDiceware, len(words), dice, dice)
+		}
+	}
+	d := fromWords(words)
+	if err != nil {
+		return d, err
+	}
+	d.dice = dice
+	d.rolls = rolls
+	d.wordsByRoll = wordsByRoll
+	return d, nil
+}
+
+// withoutRolls returns lines with the dice rolls at their start removed.
+func withoutRolls(lines []string) []string {
+	words := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if _, word, ok := cutRoll(line); ok {
+			line = word
+		}
+		words = append(words, line)
+	}
+	return words
+}
+
+// Dice returns the number of dice rolled to choose each word from a Diceware
+// word list, or 0 if the dictionary was not read from one.
+func (d *Dictionary) Dice() int {
+	return d.dice
+}
+
+// WordForRoll returns the word of a Diceware word list for roll, a string of
+// Dice() digits from 1 to 6 such as "16655". The dice index is not affected by
+// the word length limits or ASCII folding.
+func (d *Dictionary) WordForRoll(roll string) (string, bool) {
+	word, ok := d.wordsByRoll[roll]
+	return word, ok
+}
+
+// RollForWord returns the dice roll of word in a Diceware word list.
+func (d *Dictionary) RollForWord(word string) (string, bool) {
+	roll, ok := d.rolls[word]
+	return roll, ok
+}
+
+// canRoll returns whether every word of the dictionary can be looked up by its
+// dice rolls, which requires a complete Diceware word list that has not been
+// folded to ASCII or limited by word length.
+func (d *Dictionary) canRoll() bool {
+	return d.dice > 0 && !d.ascii && d.Length() == len(d.wordsByRoll)
+}
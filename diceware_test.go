@@ -0,0 +1,157 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// dicewareList returns a Diceware word list of rolls of n dice, where the
+// word of each roll is "w" followed by the roll.
+func dicewareList(n int) string {
+	rolls := []string{""}
+	for i := 0; i < n; i++ {
+		var next []string
+		for _, roll := range rolls {
+			for face := 1; face <= 6; face++ {
+				next = append(next, fmt.Sprintf("%s%d", roll, face))
+			}
+		}
+		rolls = next
+	}
+	var b strings.Builder
+	for _, roll := range rolls {
+		fmt.Fprintf(&b, "%s\tw%s\n", roll, roll)
+	}
+	return b.String()
+}
+
+func TestReadDictionaryFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input          string
+		format         string
+		expected       error
+		expectedDice   int
+		expectedLength int
+	}{
+		{dicewareList(1), "auto", nil, 1, 6},
+		{dicewareList(2), "auto", nil, 2, 36},
+		{"# EFF\n\n" + dicewareList(2), "diceware", nil, 2, 36},
+		{dicewareList(2), "foo", nil, 2, 36},
+		{dicewareList(1), "plain", nil, 0, 6},
+		{"word\nanother\n", "auto", nil, 0, 2},
+		{"word\nanother\n", "diceware", ErrInvalidDiceware, 0, 0},
+		{strings.TrimSuffix(dicewareList(2), "66\tw66\n"), "auto", ErrInvalidDiceware, 0, 0},
+		{strings.Replace(dicewareList(1), "6\tw6", "7\tw7", 1), "auto", ErrInvalidDiceware, 0, 0},
+		{strings.Replace(dicewareList(1), "6\tw6", "66\tw66", 1), "auto", ErrInvalidDiceware, 0, 0},
+		{strings.Replace(dicewareList(1), "6\tw6", "5\tw6", 1), "auto", ErrInvalidDiceware, 0, 0},
+		{strings.Replace(dicewareList(1), "w6", "w5", 1), "auto", ErrInvalidDiceware, 0, 0},
+		{strings.Replace(dicewareList(1), "w6", "w6 w7", 1), "auto", ErrInvalidDiceware, 0, 0},
+		{dicewareList(1) + "1\tw1\n", "auto", ErrInvalidDiceware, 0, 0},
+		{strings.Replace(dicewareList(1), "w1", "w1 # note", 1), "auto", nil, 1, 6},
+		{strings.Replace(dicewareList(1), "w1", "w1 # note", 1), "diceware", nil, 1, 6},
+		{"apple\n" + dicewareList(1), "auto", ErrInvalidDiceware, 0, 0},
+		{"apple\n" + dicewareList(1), "plain", nil, 0, 7},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			d, err := ReadDictionaryFormat(bytes.NewBufferString(test.input), test.format)
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("expected error %v, got %v", test.expected, err)
+			}
+			if err != nil {
+				return
+			}
+			if d.Dice() != test.expectedDice {
+				t.Errorf("expected %d dice, got %d", test.expectedDice, d.Dice())
+			}
+			if d.Length() != test.expectedLength {
+				t.Errorf("expected %d words, got %d", test.expectedLength, d.Length())
+			}
+		})
+	}
+}
+
+func TestDicewareIndex(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary(bytes.NewBufferString(dicewareList(2)))
+	if d.Length() != 36 {
+		t.Fatalf("expected 36 words, got %d", d.Length())
+	}
+	if word, ok := d.WordForRoll("35"); !ok || word != "w35" {
+		t.Errorf("expected w35, got %s", word)
+	}
+	if roll, ok := d.RollForWord("w35"); !ok || roll != "35" {
+		t.Errorf("expected 35, got %s", roll)
+	}
+	for _, roll := range []string{"", "3", "70", "355"} {
+		if word, ok := d.WordForRoll(roll); ok {
+			t.Errorf("expected no word for roll '%s', got %s", roll, word)
+		}
+	}
+	if roll, ok := d.RollForWord("w70"); ok {
+		t.Errorf("expected no roll for w70, got %s", roll)
+	}
+	// each word is two rolls of a die
+	if expected, actual := 2*math.Log2(6), d.WordEntropy(); math.Abs(actual-expected) > 1e-9 {
+		t.Errorf("expected %f bits, got %f", expected, actual)
+	}
+
+	// the dice index is not carried over when merging
+	m := Merge(d)
+	if m.Dice() != 0 {
+		t.Errorf("expected no dice, got %d", m.Dice())
+	}
+}
+
+func TestNewDictionaryInvalidDiceware(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		// a line without a roll makes it a plain word list, but the rolls
+		// of the other lines are still not words
+		{"11 apple\n12 banana\ncherry\ndate\n", []string{"date", "apple", "banana", "cherry"}},
+		{"1 a # note\n2 b\n2 c\n", []string{"a", "b", "c"}},
+		// otherwise the words are kept without their rolls
+		{"1 apple\n2 banana\n2 cherry\n", []string{"apple", "banana", "cherry"}},
+		{"1 apple\n2 banana\n3 apple\n", []string{"apple", "banana"}},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			t.Parallel()
+			d := NewDictionary(strings.NewReader(test.input))
+			if d.Dice() != 0 {
+				t.Errorf("expected no dice, got %d", d.Dice())
+			}
+			if d.Length() != len(test.expected) {
+				t.Fatalf("expected %d words, got %d", len(test.expected), d.Length())
+			}
+			for i, word := range test.expected {
+				if d.Word(i) != word {
+					t.Errorf("expected word %d to be %s, got %s", i, word, d.Word(i))
+				}
+			}
+		})
+	}
+}
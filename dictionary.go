@@ -15,67 +15,308 @@ package xkcdpwd
 
 import (
 	"bufio"
-	"bytes"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"math/big"
 	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/wfscheper/xkcdpwd/internal/langs"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
-const minEntropy = 30.0
+// DefaultMinEntropy is the minimum number of bits of entropy a passphrase
+// must have, unless changed with SetMinEntropy.
+const DefaultMinEntropy = 30.0
+
+// MaxWords is the largest number of words WordsForEntropy chooses for a
+// passphrase.
+const MaxWords = 1000
+
+// registry is the langs.Registry of the word lists that LoadDictionary can
+// load.
+var registry = langs.DefaultRegistry
+
+var (
+	// ErrUnknownLanguage is returned when there is no word list for the
+	// requested language.
+	ErrUnknownLanguage = langs.ErrUnknownLanguage
+	// ErrEmptyWordlist is returned when a word list does not contain any words.
+	ErrEmptyWordlist = errors.New("word list is empty")
+	// ErrUnreadableWordlist is returned when a word list cannot be read.
+	ErrUnreadableWordlist = errors.New("cannot read word list")
+	// ErrInvalidDiceware is returned when a Diceware word list is malformed.
+	ErrInvalidDiceware = errors.New("invalid diceware word list")
+	// ErrInvalidWordCount is returned when a passphrase is requested with
+	// fewer than one word.
+	ErrInvalidWordCount = errors.New("number of words must be at least 1")
+)
+
+// EntropyError is returned when a passphrase would have less than the minimum
+// required entropy.
+type EntropyError struct {
+	Achieved float64 // bits of entropy the passphrase would have
+	Required float64 // minimum bits of entropy required
+}
+
+func (e *EntropyError) Error() string {
+	return fmt.Sprintf("passphrase would have %0.1f bits of entropy, but at least %0.1f bits are required",
+		e.Achieved, e.Required)
+}
 
 // Dictionary wraps a word list and its length.
+//
+// The Set methods change a Dictionary in place, and are not safe to call while
+// other goroutines use it. A Dictionary that is configured when it is created,
+// or with With, and not changed afterwards is safe for concurrent use, as long
+// as its random source is. The default source is, and so are the sources set
+// with SetRandSource or WithRandSource. Use a Generator to share a
+// configuration between goroutines without having to keep this in mind.
 type Dictionary struct {
+	ascii         bool
 	capitalize    string
+	capEntropy    float64
+	dice          int
+	language      language.Tag
+	minEntropy    float64
 	minWordLength int
 	maxWordLength int
+	padding       Padding
 	randReader    io.Reader
+	rolls         map[string]string
+	separator     string
+	separators    randomSeparators
 	words         []string
+	wordsByRoll   map[string]string
+	unfolded      []string
 	start         int
 	stop          int
 }
 
 // NewDictionary scans r line-by-line and returns a Dictionary. Each line in r
 // should be a word in the dictionary. Lines beginning with a #-character are
-// considred comments and are ignored.
-func NewDictionary(r io.Reader) *Dictionary {
-	d := &Dictionary{words: []string{}, randReader: rand.Reader}
+// considred comments and are ignored. Diceware word lists, where each word is
+// preceded by its dice roll, are detected automatically. Errors reading r are
+// ignored, use ReadDictionary to detect them. The Dictionary is configured
+// with opts.
+func NewDictionary(r io.Reader, opts ...Option) *Dictionary {
+	d, _ := readDictionary(r, "auto")
+	d.apply(opts)
+	return d
+}
+
+// ReadDictionary is like NewDictionary, but returns an error wrapping
+// ErrUnreadableWordlist if r cannot be read, ErrEmptyWordlist if r does not
+// contain any words, or ErrInvalidDiceware if r looks like a Diceware word list
+// but is not a valid one.
+func ReadDictionary(r io.Reader, opts ...Option) (*Dictionary, error) {
+	return ReadDictionaryFormat(r, "auto", opts...)
+}
+
+// ReadDictionaryFormat is like ReadDictionary, but reads r in the given
+// format. The plain format has one word per line, and the diceware format has
+// a dice roll and a word per line, like the EFF word lists. The auto format
+// detects which of the two r is in. If the format passed in is not recognized,
+// then 'auto' is used.
+func ReadDictionaryFormat(r io.Reader, format string, opts ...Option) (*Dictionary, error) {
+	d, err := readDictionary(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.words) == 0 {
+		return nil, ErrEmptyWordlist
+	}
+	d.apply(opts)
+	return d, nil
+}
+
+func readDictionary(r io.Reader, format string) (*Dictionary, error) {
+	lines := []string{}
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
-		w := scanner.Text()
-		i := strings.IndexRune(w, '#')
-		switch {
-		case i < 0:
-			w = strings.TrimSpace(w)
-		case i > 0:
-			w = strings.TrimSpace(w[:i])
-		default:
-			w = ""
-		}
-		if w != "" {
-			d.words = append(d.words, w)
-			wLength := len(w)
-			if d.MaxWordLength() < wLength {
-				d.SetMaxWordLength(wLength)
-			}
-			if d.MinWordLength() > wLength || d.MinWordLength() == 0 {
-				d.SetMinWordLength(wLength)
-			}
+		if line := strings.TrimSpace(scanner.Text()); line != "" && line[0] != '#' {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fromWords(plainWords(lines)), fmt.Errorf("%w: %s", ErrUnreadableWordlist, err)
+	}
+	if format == "diceware" || format != "plain" && isDiceware(lines) {
+		return readDiceware(lines)
+	}
+	return fromWords(plainWords(lines)), nil
+}
+
+// plainWords returns the words of a plain word list, with trailing comments
+// removed.
+func plainWords(lines []string) []string {
+	words := make([]string, 0, len(lines))
+	for _, w := range lines {
+		if w = stripComment(w); w != "" {
+			// compose accented letters, so that they count as a single
+			// character regardless of how the word list was written
+			words = append(words, norm.NFC.String(w))
+		}
+	}
+	return words
+}
+
+// stripComment returns line without its trailing comment, if any.
+func stripComment(line string) string {
+	if i := strings.IndexRune(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
+
+// fromWords returns a Dictionary of words, which it sorts by length.
+func fromWords(words []string) *Dictionary {
+	d := &Dictionary{
+		words:      words,
+		minEntropy: DefaultMinEntropy,
+		randReader: rand.Reader,
+		separator:  " ",
+		separators: newRandomSeparators("", "gap"),
+	}
+	for _, w := range words {
+		wLength := wordLength(w)
+		if d.maxWordLength < wLength {
+			d.maxWordLength = wLength
+		}
+		if d.minWordLength > wLength || d.minWordLength == 0 {
+			d.minWordLength = wLength
 		}
 	}
 	// sort words according to length, so that we can more easily
 	// filter them later
-	sort.Slice(d.words, func(i, j int) bool {
-		return len(d.words[i]) < len(d.words[j])
+	sort.SliceStable(d.words, func(i, j int) bool {
+		return wordLength(d.words[i]) < wordLength(d.words[j])
 	})
+	d.updateStart()
+	d.updateStop()
+	return d
+}
+
+// Merge returns a Dictionary of the words in all of dicts, with duplicate
+// words removed. The word length limits, capitalization and other settings of
+// dicts are not carried over. The language of the new dictionary is the
+// language of dicts if they all agree, and language.Und otherwise.
+func Merge(dicts ...*Dictionary) *Dictionary {
+	var words []string
+	seen := map[string]bool{}
+	tag := language.Und
+	for i, d := range dicts {
+		switch {
+		case i == 0:
+			tag = d.language
+		case tag != d.language:
+			tag = language.Und
+		}
+		for _, w := range d.words {
+			if !seen[w] {
+				seen[w] = true
+				words = append(words, w)
+			}
+		}
+	}
+	d := fromWords(words)
+	d.SetLanguage(tag)
 	return d
 }
 
+// wordLength returns the number of characters in word.
+func wordLength(word string) int {
+	return utf8.RuneCountInString(word)
+}
+
+// Language returns the language of the words in the dictionary.
+func (d *Dictionary) Language() language.Tag {
+	return d.language
+}
+
+// SetLanguage sets the language of the words in the dictionary, which
+// determines how they are capitalized. Dictionaries read from a word list
+// default to language.Und, which uses capitalization rules that are reasonable
+// for most languages.
+func (d *Dictionary) SetLanguage(tag language.Tag) {
+	d.language = tag
+	d.updateCapitalizeEntropy()
+}
+
+// ASCII returns whether the words of the dictionary are folded to ASCII.
+func (d *Dictionary) ASCII() bool {
+	return d.ascii
+}
+
+// SetASCII sets whether the words of the dictionary are folded to ASCII, by
+// stripping accents from letters. Words that still contain non-ASCII
+// characters are dropped, and words that fold to the same ASCII word are only
+// kept once.
+func (d *Dictionary) SetASCII(ascii bool) {
+	switch {
+	case ascii && !d.ascii:
+		d.unfolded = d.words
+		d.words = foldASCII(d.words)
+	case !ascii && d.ascii:
+		d.words = d.unfolded
+		d.unfolded = nil
+	}
+	d.ascii = ascii
+	d.updateStart()
+	d.updateStop()
+	d.updateCapitalizeEntropy()
+}
+
+// foldASCII returns the unique ASCII forms of words, in the same order.
+func foldASCII(words []string) []string {
+	// decompose letters into a base letter and combining marks, then drop
+	// the marks, e.g. á becomes a and ñ becomes n
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)))
+	seen := make(map[string]bool, len(words))
+	folded := make([]string, 0, len(words))
+	for _, word := range words {
+		ascii, _, err := transform.String(t, word)
+		if err != nil || !isASCII(ascii) || seen[ascii] {
+			continue
+		}
+		seen[ascii] = true
+		folded = append(folded, ascii)
+	}
+	// folding keeps the number of characters of precomposed letters, but
+	// not necessarily of everything else
+	sort.SliceStable(folded, func(i, j int) bool {
+		return wordLength(folded[i]) < wordLength(folded[j])
+	})
+	return folded
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// SetRandSource sets the source of the random bytes used to generate
+// passphrases, which is crypto/rand.Reader by default. The source is wrapped
+// in a HealthCheckedReader that assumes full entropy, unless it already is
+// one, so that generation fails with ErrHealthCheck if the source gets stuck.
+// Use a DiceReader to generate passphrases from dice rolls.
+func (d *Dictionary) SetRandSource(r io.Reader) {
+	d.randReader = healthChecked(r)
+}
+
 // Capitalize returns the current capitalizaton strategy.
 func (d *Dictionary) Capitalize() string {
 	return d.capitalize
@@ -89,9 +330,80 @@ func (d *Dictionary) SetCapitalize(s string) {
 	default:
 		d.capitalize = "none"
 	}
+	d.updateCapitalizeEntropy()
+}
+
+// Separator returns the separator between the words of a passphrase.
+func (d *Dictionary) Separator() string {
+	return d.separator
+}
+
+// SetSeparator sets the separator between the words of a passphrase, which is
+// a space by default.
+func (d *Dictionary) SetSeparator(s string) {
+	d.separator = s
+}
+
+// SeparatorSet returns the characters that separators are chosen from at
+// random, or an empty string if the separator is fixed.
+func (d *Dictionary) SeparatorSet() string {
+	return d.separators.set
+}
+
+// SetSeparatorSet sets the characters that the separators between the words
+// of a passphrase are chosen from at random, which replaces the fixed
+// separator. An empty set goes back to the fixed separator.
+func (d *Dictionary) SetSeparatorSet(set string) {
+	d.separators = newRandomSeparators(set, d.separators.policy)
+}
+
+// SeparatorPolicy returns how often random separators are chosen.
+func (d *Dictionary) SeparatorPolicy() string {
+	return d.separators.policy
+}
+
+// SetSeparatorPolicy sets how often random separators are chosen. The gap
+// policy chooses a separator for each gap between words, and the phrase
+// policy chooses one separator for the whole passphrase. If the string passed
+// in is not a recognized policy, then 'gap' is used.
+func (d *Dictionary) SetSeparatorPolicy(s string) {
+	d.separators = newRandomSeparators(d.separators.set, s)
+}
+
+// Padding returns the digits and symbols added to a passphrase.
+func (d *Dictionary) Padding() Padding {
+	return d.padding
+}
+
+// SetPadding sets the digits and symbols added to a passphrase, which is none
+// by default. Counts less than 0 are taken to mean none.
+func (d *Dictionary) SetPadding(pad Padding) {
+	d.padding = pad.normalize()
+}
+
+// MinEntropy returns the minimum number of bits of entropy a passphrase must
+// have.
+func (d *Dictionary) MinEntropy() float64 {
+	return d.minEntropy
+}
+
+// SetMinEntropy sets the minimum number of bits of entropy a passphrase must
+// have. Values less than 0 are taken to mean no minimum, and NaN is taken to
+// mean DefaultMinEntropy.
+func (d *Dictionary) SetMinEntropy(bits float64) {
+	d.minEntropy = minEntropy(bits)
+}
+
+// minEntropy returns the minimum entropy that bits is taken to mean. NaN
+// compares false with every number, so it would turn the minimum off.
+func minEntropy(bits float64) float64 {
+	if math.IsNaN(bits) {
+		return DefaultMinEntropy
+	}
+	return math.Max(bits, 0)
 }
 
-// MaxWordLength returns the current max word length
+// MaxWordLength returns the current max word length, in characters
 func (d *Dictionary) MaxWordLength() int {
 	return d.maxWordLength
 }
@@ -100,6 +412,7 @@ func (d *Dictionary) MaxWordLength() int {
 func (d *Dictionary) SetMaxWordLength(n int) {
 	d.maxWordLength = n
 	d.updateStop()
+	d.updateCapitalizeEntropy()
 }
 
 func (d *Dictionary) updateStop() {
@@ -110,7 +423,7 @@ func (d *Dictionary) updateStop() {
 	end := len(d.words) - 1
 	for i := range d.words {
 		word := d.words[end-i]
-		if len(word) <= d.maxWordLength {
+		if wordLength(word) <= d.maxWordLength {
 			d.stop = end - i + 1
 			return
 		}
@@ -118,7 +431,7 @@ func (d *Dictionary) updateStop() {
 	d.stop = 0
 }
 
-// MinWordLength returns the current minimum word length
+// MinWordLength returns the current minimum word length, in characters
 func (d *Dictionary) MinWordLength() int {
 	return d.minWordLength
 }
@@ -127,6 +440,7 @@ func (d *Dictionary) MinWordLength() int {
 func (d *Dictionary) SetMinWordLength(n int) {
 	d.minWordLength = n
 	d.updateStart()
+	d.updateCapitalizeEntropy()
 }
 
 func (d *Dictionary) updateStart() {
@@ -135,7 +449,7 @@ func (d *Dictionary) updateStart() {
 		return
 	}
 	for idx, word := range d.words {
-		if len(word) >= d.minWordLength {
+		if wordLength(word) >= d.minWordLength {
 			d.start = idx
 			return
 		}
@@ -145,7 +459,136 @@ func (d *Dictionary) updateStart() {
 
 // Entropy returns the number of bits of entropy the current dictionary configuration can support.
 func (d *Dictionary) Entropy(n int) float64 {
-	return float64(n) * math.Log2(float64(d.Length()))
+	return d.PassphraseEntropy(n).Bits()
+}
+
+// PassphraseEntropy returns the entropy of an n-word passphrase from the
+// current dictionary configuration, itemized by source.
+func (d *Dictionary) PassphraseEntropy(n int) Entropy {
+	if d.Length() == 0 {
+		return Entropy{}
+	}
+	digits, symbols := d.padding.entropy()
+	return Entropy{
+		Words:          float64(n) * math.Log2(float64(d.Length())),
+		Capitalization: float64(n) * d.capEntropy,
+		Separators:     d.separators.entropy(d.padding.gaps(n)),
+		Digits:         digits,
+		Symbols:        symbols,
+	}
+}
+
+// WordEntropy returns the number of bits of entropy each word of a passphrase
+// contributes, including the randomness added by the capitalization strategy.
+func (d *Dictionary) WordEntropy() float64 {
+	if d.Length() == 0 {
+		return 0
+	}
+	return math.Log2(float64(d.Length())) + d.capEntropy
+}
+
+// updateCapitalizeEntropy updates the number of bits the capitalization
+// strategy adds to each word. It scans the words, so it is done when the
+// settings change, rather than for each passphrase.
+func (d *Dictionary) updateCapitalizeEntropy() {
+	d.capEntropy = d.capitalizeEntropy()
+}
+
+// capitalizeEntropy returns the number of bits the capitalization strategy
+// adds to each word. Only the random strategy adds any, one bit for each
+// letter that has an upper case form. Words with fewer letters have more
+// likely capitalizations, so the count is that of the word with the fewest
+// letters, which is what an attacker who knows the settings faces at worst.
+func (d *Dictionary) capitalizeEntropy() float64 {
+	if d.capitalize != "random" || d.Length() == 0 {
+		return 0
+	}
+	upper := cases.Upper(d.language)
+	fewest := -1
+	for _, word := range d.words[d.start:d.stop] {
+		letters := 0
+		for _, c := range word {
+			switch {
+			case c < utf8.RuneSelf:
+				// fast path for ASCII, where only lower case letters change
+				if 'a' <= c && c <= 'z' {
+					letters++
+				}
+			case upper.String(string(c)) != string(c):
+				letters++
+			}
+		}
+		if fewest < 0 || letters < fewest {
+			fewest = letters
+		}
+		if fewest == 0 {
+			break
+		}
+	}
+	return float64(fewest)
+}
+
+// WordsForEntropy returns the number of words a passphrase needs to have at
+// least bits of entropy, counting the entropy of the padding and random
+// separators. A passphrase always has at least one word. An error is returned
+// if bits is not a finite number greater than 0, or if the current dictionary
+// configuration cannot reach bits of entropy with at most MaxWords words. If no
+// words are within the word length limits, then the error wraps
+// ErrEmptyWordlist.
+func (d *Dictionary) WordsForEntropy(bits float64) (int, error) {
+	if err := checkEntropy(bits); err != nil {
+		return 0, err
+	}
+	if d.Length() == 0 {
+		return 0, fmt.Errorf("%w: no words match the word length limits", ErrEmptyWordlist)
+	}
+	perWord := d.WordEntropy()
+	if d.separators.policy == "gap" {
+		perWord += d.separators.bits()
+	}
+	if perWord <= 0 {
+		// more words add nothing, but the padding, and the separator of
+		// the phrase policy once there are two words, may be enough
+		for n := 1; n <= 2; n++ {
+			if d.Entropy(n) >= bits {
+				return n, nil
+			}
+		}
+		return 0, fmt.Errorf("dictionary of %d words cannot reach %0.1f bits of entropy", d.Length(), bits)
+	}
+	// estimate as if each word adds a separator, then correct the estimate
+	// for the separators of the padding and the phrase policy. The estimate
+	// is checked before it is converted, so that it cannot overflow.
+	digits, symbols := d.padding.entropy()
+	estimate := math.Max(math.Ceil((bits-digits-symbols)/perWord), 1)
+	if estimate > MaxWords+1 {
+		return 0, tooManyWords(bits)
+	}
+	n := int(estimate)
+	for n > 1 && d.Entropy(n-1) >= bits {
+		n--
+	}
+	for n <= MaxWords && d.Entropy(n) < bits {
+		n++
+	}
+	if n > MaxWords {
+		return 0, tooManyWords(bits)
+	}
+	return n, nil
+}
+
+// checkEntropy returns an error if bits is not a valid target entropy.
+func checkEntropy(bits float64) error {
+	if math.IsNaN(bits) || math.IsInf(bits, 0) || bits <= 0 {
+		return fmt.Errorf("entropy must be a finite number greater than 0, got %0.1f", bits)
+	}
+	return nil
+}
+
+// tooManyWords returns the error for a target entropy that needs more than
+// MaxWords words.
+func tooManyWords(bits float64) error {
+	return fmt.Errorf("%g bits of entropy would need more than %d words", bits, MaxWords)
 }
 
 // Length returns the number of words in the Dictionary.
@@ -166,53 +609,221 @@ func (d *Dictionary) Word(idx int) string {
 	return d.words[d.start+idx]
 }
 
-// Passphrase returns a slice of n randomly chosen words. If the dictionary
-// cannot support the minimum entropy, then an error is returned.
+// Passphrase returns a slice of n randomly chosen words. If the passphrase
+// would have less than the minimum entropy, then an *EntropyError is returned.
 func (d *Dictionary) Passphrase(n int) ([]string, error) {
-	if d.Entropy(n) < minEntropy {
-		return nil, fmt.Errorf("dictionary cannot support more than %0.0f bits of entropy", minEntropy)
+	words, _, err := d.PassphraseWithEntropy(n)
+	return words, err
+}
+
+// PassphraseWithEntropy is like Passphrase, but also returns the entropy of
+// the passphrase.
+func (d *Dictionary) PassphraseWithEntropy(n int) ([]string, Entropy, error) {
+	p, err := d.Generate(n)
+	return p.Words, p.Entropy, err
+}
+
+// Generate returns a Passphrase of n randomly chosen words. If n is less than
+// one, then an error wrapping ErrInvalidWordCount is returned. If no words are
+// within the word length limits, then an error wrapping ErrEmptyWordlist is
+// returned, and if the passphrase would have less than the minimum entropy,
+// then an *EntropyError is returned.
+// When an error is returned the Passphrase has no words, but still has the
+// entropy and settings it would have been generated with, unless n is invalid.
+func (d *Dictionary) Generate(n int) (Passphrase, error) {
+	if n < 1 {
+		return Passphrase{}, fmt.Errorf("%w, got %d", ErrInvalidWordCount, n)
+	}
+	p := Passphrase{
+		Separator: d.separator,
+		Language:  d.language,
+		Entropy:   d.PassphraseEntropy(n),
+		Settings:  d.Settings(),
 	}
-	dictLengh := big.NewInt(int64(d.Length() - 1))
+	if err := d.Check(n); err != nil {
+		return p, err
+	}
+	c := d.newCasers()
 	words := make([]string, n)
+	indexes := make([]int, n)
 	for i := 0; i < n; i++ {
-		idx, err := rand.Int(d.randReader, dictLengh)
+		word, idx, err := d.chooseWord(c)
 		if err != nil {
-			return nil, fmt.Errorf("cannot generate random words: %s", err)
-		}
-		word := d.words[d.start+int(idx.Int64())]
-		switch d.capitalize {
-		case "all":
-			word = strings.ToUpper(word)
-		case "first":
-			word = strings.ToUpper(word[0:1]) + word[1:]
-		case "random":
-			for j := range word {
-				choice, err := rand.Int(d.randReader, big.NewInt(int64(9)))
-				if err != nil {
-					return []string{}, err
-				}
-				if int(choice.Int64()) < 5 {
-					switch j {
-					case 0:
-						word = strings.ToUpper(word[0:1]) + word[1:]
-					case len(word) - 1:
-						word = word[0:j] + strings.ToUpper(word[j:])
-					default:
-						word = word[0:j] + strings.ToUpper(word[j:j+1]) + word[j+1:]
-					}
-				}
-			}
+			return p, err
 		}
 		words[i] = word
+		indexes[i] = idx
+	}
+	before, after, err := d.padding.generate(d.randReader)
+	if err != nil {
+		return p, err
+	}
+	p.Words = words
+	p.Indexes = indexes
+	p.Before, p.After, p.Placement = before, after, d.padding.Placement
+	if err := d.separators.choose(&p, d.randReader); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// Check returns the error Generate would return for an n-word passphrase
+// before choosing any words, or nil if there is none.
+func (d *Dictionary) Check(n int) error {
+	if n < 1 {
+		return fmt.Errorf("%w, got %d", ErrInvalidWordCount, n)
+	}
+	if d.Length() == 0 {
+		return fmt.Errorf("%w: no words match the word length limits", ErrEmptyWordlist)
+	}
+	if bits := d.Entropy(n); bits < d.minEntropy {
+		return &EntropyError{Achieved: bits, Required: d.minEntropy}
+	}
+	return nil
+}
+
+// Settings returns the settings passphrases are generated with.
+func (d *Dictionary) Settings() Settings {
+	return Settings{
+		ASCII:         d.ascii,
+		Capitalize:    d.capitalize,
+		MinWordLength: d.minWordLength,
+		MaxWordLength: d.maxWordLength,
+		MinEntropy:    d.minEntropy,
+	}
+}
+
+// casers capitalize words in the language of a dictionary. They are not safe
+// for concurrent use, so each passphrase gets its own.
+type casers struct {
+	upper cases.Caser
+	title cases.Caser
+}
+
+func (d *Dictionary) newCasers() casers {
+	return casers{
+		upper: cases.Upper(d.language),
+		title: cases.Title(d.language, cases.NoLower),
+	}
+}
+
+// chooseWord returns a randomly chosen word, capitalized according to the
+// capitalization strategy, and its index in the dictionary.
+func (d *Dictionary) chooseWord(c casers) (string, int, error) {
+	word, idx, err := d.randomWord()
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot generate random words: %w", err)
+	}
+	switch d.capitalize {
+	case "all":
+		word = c.upper.String(word)
+	case "first":
+		word = c.title.String(word)
+	case "random":
+		// each letter is upper-cased with probability 1/2
+		var b strings.Builder
+		for _, r := range word {
+			choice, err := rand.Int(d.randReader, big.NewInt(2))
+			if err != nil {
+				return "", 0, fmt.Errorf("cannot generate random words: %w", err)
+			}
+			if choice.Sign() == 0 {
+				b.WriteString(c.upper.String(string(r)))
+			} else {
+				b.WriteRune(r)
+			}
+		}
+		word = b.String()
+	}
+	return word, idx, nil
+}
+
+// randomWord returns a uniformly chosen word and its index in the dictionary.
+func (d *Dictionary) randomWord() (string, int, error) {
+	if dice, ok := d.randReader.(*DiceReader); ok && d.canRoll() {
+		// look the word up by its rolls, so that it can be checked against
+		// the printed word list
+		roll, err := dice.Roll(d.dice)
+		if err != nil {
+			return "", 0, err
+		}
+		word := d.wordsByRoll[roll]
+		for idx, w := range d.words[d.start:d.stop] {
+			if w == word {
+				return word, idx, nil
+			}
+		}
+	}
+	// rand.Int returns a uniform value in [0, max), so max is the number of
+	// words to choose from
+	idx, err := rand.Int(d.randReader, big.NewInt(int64(d.Length())))
+	if err != nil {
+		return "", 0, err
+	}
+	return d.words[d.start+int(idx.Int64())], int(idx.Int64()), nil
+}
+
+// PassphraseForEntropy returns a slice of randomly chosen words with at least
+// bits of entropy. The number of words is chosen by WordsForEntropy.
+func (d *Dictionary) PassphraseForEntropy(bits float64) ([]string, error) {
+	n, err := d.WordsForEntropy(bits)
+	if err != nil {
+		return nil, err
+	}
+	return d.Passphrase(n)
+}
+
+// LoadDictionary returns the dictionary for the word list that best matches
+// the language tag lang, and sets its language to the matched tag. The word
+// lists are those of a Registry of the environment of the process. An empty
+// lang selects the default language. The Dictionary is then configured with
+// opts.
+// If no word list matches lang, then the returned error wraps
+// ErrUnknownLanguage.
+func LoadDictionary(lang string, opts ...Option) (*Dictionary, error) {
+	r, err := registry()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnreadableWordlist, err)
 	}
-	return words, nil
+	return (&Registry{r}).LoadDictionary(lang, opts...)
 }
 
-// GetDict returns the dictionary associated with the language code lang.
+// LoadDictionaries returns a Dictionary that merges the word lists that best
+// match each of langs, as LoadDictionary finds them. See Merge.
+func LoadDictionaries(langs ...string) (*Dictionary, error) {
+	if len(langs) == 0 {
+		return LoadDictionary("")
+	}
+	dicts := make([]*Dictionary, 0, len(langs))
+	for _, lang := range langs {
+		d, err := LoadDictionary(lang)
+		if err != nil {
+			return nil, err
+		}
+		dicts = append(dicts, d)
+	}
+	return Merge(dicts...), nil
+}
+
+// Languages returns the language tags of the word lists that LoadDictionary
+// can load, with the default language first.
+func Languages() ([]language.Tag, error) {
+	r, err := registry()
+	if err != nil {
+		return nil, err
+	}
+	return (&Registry{r}).Languages(), nil
+}
+
+// GetDict returns the dictionary associated with the language code lang, or
+// nil if there is no such dictionary.
+//
+// Deprecated: Use LoadDictionary, which reports why a dictionary could not be
+// loaded.
 func GetDict(lang string) *Dictionary {
-	data, err := langs.GetLanguage(lang)
+	d, err := LoadDictionary(lang)
 	if err != nil {
 		return nil
 	}
-	return NewDictionary(bytes.NewBuffer(data))
+	return d
 }
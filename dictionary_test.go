@@ -15,23 +15,48 @@ package xkcdpwd
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/rand"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wfscheper/xkcdpwd/internal/langs"
+	"golang.org/x/text/language"
 )
 
+// TestMain limits the languages to the embedded word lists, so that the tests
+// do not depend on the word lists installed on the host.
+func TestMain(m *testing.M) {
+	r, err := langs.NewRegistry(langs.Embedded)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot load the embedded languages: %v\n", err)
+		os.Exit(2)
+	}
+	registry = func() (*langs.Registry, error) { return r, nil }
+	os.Exit(m.Run())
+}
+
 // newDictionary returns a Dictionary of the slice words. words is assumed to
 // already be sorted by word length, and the Dictionary will be configured with
 // a non-random randReader.
 func newDictionary(words []string) (d *Dictionary) {
 	d = &Dictionary{
+		minEntropy: DefaultMinEntropy,
 		randReader: constantReader(0),
 		words:      words,
 	}
-	d.SetMaxWordLength(len(words[len(words)-1]))
-	d.SetMinWordLength(len(words[0]))
+	d.SetMaxWordLength(wordLength(words[len(words)-1]))
+	d.SetMinWordLength(wordLength(words[0]))
 	return
 }
 
@@ -44,6 +69,66 @@ func (c constantReader) Read(p []byte) (int, error) {
 	return len(p), nil
 }
 
+// hashReader is a deterministic stream of well-distributed bytes, produced by
+// hashing a counter. It keeps the statistical tests reproducible.
+type hashReader struct {
+	counter uint64
+	buf     []byte
+}
+
+func (h *hashReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(h.buf) == 0 {
+			var block [8]byte
+			binary.BigEndian.PutUint64(block[:], h.counter)
+			h.counter++
+			sum := sha256.Sum256(block[:])
+			h.buf = sum[:]
+		}
+		c := copy(p[n:], h.buf)
+		h.buf = h.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+// chiSquare returns Pearson's chi-square statistic for observed counts against
+// a uniform distribution.
+func chiSquare(observed []int) float64 {
+	total := 0
+	for _, o := range observed {
+		total += o
+	}
+	expected := float64(total) / float64(len(observed))
+	var stat float64
+	for _, o := range observed {
+		diff := float64(o) - expected
+		stat += diff * diff / expected
+	}
+	return stat
+}
+
+// chiSquareCritical returns the approximate critical value of the chi-square
+// distribution with df degrees of freedom at a significance level of 0.001,
+// using the Wilson-Hilferty transformation.
+func chiSquareCritical(df int) float64 {
+	const z = 3.090 // standard normal quantile for p = 0.999
+	k := float64(df)
+	v := 1 - 2/(9*k) + z*math.Sqrt(2/(9*k))
+	return k * v * v * v
+}
+
+// assertUniform fails t if observed is not consistent with a uniform
+// distribution.
+func assertUniform(t *testing.T, observed []int) {
+	t.Helper()
+	stat := chiSquare(observed)
+	if critical := chiSquareCritical(len(observed) - 1); stat > critical {
+		t.Errorf("distribution is not uniform: chi-square %.2f > %.2f\n%v", stat, critical, observed)
+	}
+}
+
 func TestNewDictionary(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -82,6 +167,25 @@ func TestNewDictionary(t *testing.T) {
 			expectedMaxWordLength: 7,
 			expectedMinWordLength: 4,
 		},
+		{
+			data:                  "antidisestablishmentarianism\nword\nanother\n",
+			expectedWords:         []string{"word", "another", "antidisestablishmentarianism"},
+			expectedMaxWordLength: 28,
+			expectedMinWordLength: 4,
+		},
+		{
+			data:                  "acción\nniño\n",
+			expectedWords:         []string{"niño", "acción"},
+			expectedMaxWordLength: 6,
+			expectedMinWordLength: 4,
+		},
+		{
+			// decomposed accents are composed into single characters
+			data:                  "accio\u0301n\nnin\u0303o\n",
+			expectedWords:         []string{"niño", "acción"},
+			expectedMaxWordLength: 6,
+			expectedMinWordLength: 4,
+		},
 	}
 	for idx, test := range tests {
 		r := bytes.NewBufferString(test.data)
@@ -94,6 +198,9 @@ func TestNewDictionary(t *testing.T) {
 				if test.expectedMinWordLength != d.MinWordLength() {
 					t.Errorf("expected min word length %d, got %d", test.expectedMinWordLength, d.MinWordLength())
 				}
+				if len(test.expectedWords) != d.Length() {
+					t.Errorf("expected length %d, got %d", len(test.expectedWords), d.Length())
+				}
 			} else {
 				t.Errorf("expected %v words, got %v", test.expectedWords, d.words)
 			}
@@ -230,6 +337,86 @@ func TestSetMinWordLength(t *testing.T) {
 	}
 }
 
+func TestWordLengthSpanish(t *testing.T) {
+	t.Parallel()
+	d, err := LoadDictionary("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetMinWordLength(6)
+	d.SetMaxWordLength(6)
+	found := map[string]bool{}
+	for i := 0; i < d.Length(); i++ {
+		word := d.Word(i)
+		if n := utf8.RuneCountInString(word); n != 6 {
+			t.Errorf("expected 6 characters, got %d in '%s'", n, word)
+		}
+		found[word] = true
+	}
+	for _, word := range []string{"acción", "fuerza", "manera"} {
+		if !found[word] {
+			t.Errorf("expected '%s' in the dictionary", word)
+		}
+	}
+	for _, word := range []string{"años", "señor"} {
+		if found[word] {
+			t.Errorf("did not expect '%s' in the dictionary", word)
+		}
+	}
+}
+
+func TestSetASCII(t *testing.T) {
+	t.Parallel()
+	words := []string{"año", "ano", "niño", "árbol", "straße", "acción", "æsir", "über"}
+	d := NewDictionary(bytes.NewBufferString(strings.Join(words, "\n")))
+	d.SetASCII(true)
+	if !d.ASCII() {
+		t.Error("expected ASCII to be set")
+	}
+	expected := []string{"ano", "nino", "uber", "arbol", "accion"}
+	if !reflect.DeepEqual(expected, d.words) {
+		t.Errorf("expected %v, got %v", expected, d.words)
+	}
+	if d.Length() != len(expected) {
+		t.Errorf("expected length %d, got %d", len(expected), d.Length())
+	}
+	if entropy := d.Entropy(1); math.Abs(entropy-math.Log2(5)) > 1e-9 {
+		t.Errorf("expected %f bits of entropy, got %f", math.Log2(5), entropy)
+	}
+
+	// the length filters apply to the folded words
+	d.SetMinWordLength(5)
+	if d.Length() != 2 {
+		t.Errorf("expected length 2, got %d", d.Length())
+	}
+
+	d.SetASCII(false)
+	if d.ASCII() {
+		t.Error("expected ASCII to be unset")
+	}
+	if d.Length() != 3 {
+		t.Errorf("expected length 3, got %d", d.Length())
+	}
+}
+
+func TestSetASCIISpanish(t *testing.T) {
+	t.Parallel()
+	d, err := LoadDictionary("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	length := d.Length()
+	d.SetASCII(true)
+	if d.Length() >= length {
+		t.Errorf("expected fewer than %d words, got %d", length, d.Length())
+	}
+	for i := 0; i < d.Length(); i++ {
+		if word := d.Word(i); !isASCII(word) {
+			t.Errorf("expected an ASCII word, got '%s'", word)
+		}
+	}
+}
+
 func TestWord(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -358,20 +545,148 @@ func TestWordWithMin(t *testing.T) {
 	}
 }
 
+func TestReadDictionary(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		r        io.Reader
+		expected error
+	}{
+		{"words", bytes.NewBufferString("word\nanother\n"), nil},
+		{"empty", bytes.NewBufferString(""), ErrEmptyWordlist},
+		{"comments", bytes.NewBufferString("# comment\n  # comment2\n"), ErrEmptyWordlist},
+		{"unreadable", iotest.ErrReader(errors.New("boom")), ErrUnreadableWordlist},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			d, err := ReadDictionary(test.r)
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("expected error %v, got %v", test.expected, err)
+			}
+			if err != nil && d != nil {
+				t.Errorf("expected nil dictionary, got %v", d)
+			}
+		})
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		lang     string
+		expected error
+	}{
+		{"", nil},
+		{"en", nil},
+		{"en_US.UTF-8", nil},
+		{"es", nil},
+		{"es-MX", nil},
+		{"de", ErrUnknownLanguage},
+		{"foo", ErrUnknownLanguage},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.lang, func(t *testing.T) {
+			d, err := LoadDictionary(test.lang)
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("expected error %v, got %v", test.expected, err)
+			}
+			if err == nil && d.Length() == 0 {
+				t.Error("expected a non-empty dictionary")
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	a := NewDictionary(bytes.NewBufferString("word\nanother\nsol\n"))
+	a.SetLanguage(language.English)
+	a.SetMinWordLength(5)
+	b := NewDictionary(bytes.NewBufferString("sol\npalabra\nword\nes\n"))
+	b.SetLanguage(language.Spanish)
+
+	d := Merge(a, b)
+	expected := []string{"es", "sol", "word", "another", "palabra"}
+	if !reflect.DeepEqual(expected, d.words) {
+		t.Errorf("expected %v, got %v", expected, d.words)
+	}
+	if d.Length() != len(expected) {
+		t.Errorf("expected length %d, got %d", len(expected), d.Length())
+	}
+	if d.MinWordLength() != 2 || d.MaxWordLength() != 7 {
+		t.Errorf("expected word lengths 2 to 7, got %d to %d", d.MinWordLength(), d.MaxWordLength())
+	}
+	if d.Language() != language.Und {
+		t.Errorf("expected %v, got %v", language.Und, d.Language())
+	}
+
+	b.SetLanguage(language.English)
+	if d := Merge(a, b); d.Language() != language.English {
+		t.Errorf("expected %v, got %v", language.English, d.Language())
+	}
+}
+
+func TestLoadDictionaries(t *testing.T) {
+	t.Parallel()
+	en, err := LoadDictionary("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	es, err := LoadDictionary("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := LoadDictionaries("en", "es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// some words, like "general", are in both lists
+	if d.Length() <= es.Length() || d.Length() >= en.Length()+es.Length() {
+		t.Errorf("expected between %d and %d words, got %d", es.Length(), en.Length()+es.Length(), d.Length())
+	}
+	for i := 1; i < len(d.words); i++ {
+		if wordLength(d.words[i-1]) > wordLength(d.words[i]) {
+			t.Fatalf("words are not sorted by length: '%s' before '%s'", d.words[i-1], d.words[i])
+		}
+	}
+
+	d, err = LoadDictionaries("es", "es-MX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Length() != es.Length() || d.Language() != language.Spanish {
+		t.Errorf("expected %d %v words, got %d %v words", es.Length(), language.Spanish, d.Length(), d.Language())
+	}
+
+	if _, err := LoadDictionaries("en", "de"); !errors.Is(err, ErrUnknownLanguage) {
+		t.Errorf("expected ErrUnknownLanguage, got %v", err)
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	tags, err := Languages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []language.Tag{language.English, language.Spanish}
+	if !reflect.DeepEqual(expected, tags) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+}
+
 func TestGetDict(t *testing.T) {
 	d := GetDict("en")
-	actual := d.Word(0)
-	if d != nil {
-		if "able" != actual {
-			t.Errorf("expected 'able', got '%s'", actual)
-		}
+	if d == nil {
+		t.Fatal("expected English dictionary, got nil")
 	}
-	// matcher will try *really* hard to give a match
-	d = GetDict("foo")
-	if d != nil {
-		if "able" != actual {
-			t.Errorf("expected 'able', got '%s'", actual)
-		}
+	if actual := d.Word(0); actual != "able" {
+		t.Errorf("expected 'able', got '%s'", actual)
+	}
+	if d = GetDict("foo"); d != nil {
+		t.Errorf("expected nil dictionary, got %v", d)
 	}
 }
 
@@ -412,6 +727,312 @@ func TestCapitalizeFirst(t *testing.T) {
 	}
 }
 
+func TestWordEntropy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		words      []string
+		capitalize string
+		expected   float64
+	}{
+		{[]string{"a"}, "none", 0},
+		{[]string{"a", "b"}, "none", 1},
+		{[]string{"a", "b", "c", "d"}, "all", 2},
+		{[]string{"a", "b", "c", "d"}, "first", 2},
+		{[]string{"a", "b", "c", "d"}, "random", 3},
+		{[]string{"ab", "cd", "ef", "gh"}, "random", 4},
+		{[]string{"abc", "ab", "c3d", "def"}, "random", 4},
+		{[]string{"12", "ab", "c3", "de"}, "random", 2},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			d := newDictionary(test.words)
+			d.SetCapitalize(test.capitalize)
+			if actual := d.WordEntropy(); math.Abs(actual-test.expected) > 1e-9 {
+				t.Errorf("expected %f bits, got %f", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPassphraseEntropy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		capitalize string
+		expected   Entropy
+	}{
+		{"none", Entropy{Words: 8}},
+		{"first", Entropy{Words: 8}},
+		{"all", Entropy{Words: 8}},
+		{"random", Entropy{Words: 8, Capitalization: 4}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.capitalize, func(t *testing.T) {
+			d := newDictionary([]string{"ab", "cd", "e1", "g2", "hij", "klm", "nop", "qrs"})
+			d.SetCapitalize(test.capitalize)
+			d.SetMaxWordLength(2)
+			d.SetMinEntropy(0)
+			if actual := d.PassphraseEntropy(4); actual != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, actual)
+			}
+			words, actual, err := d.PassphraseWithEntropy(4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(words) != 4 {
+				t.Errorf("expected 4 words, got %v", words)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWordsForEntropy(t *testing.T) {
+	t.Parallel()
+	sixteen := strings.Split("a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p", ",")
+	tests := []struct {
+		words      []string
+		capitalize string
+		bits       float64
+		expected   int
+		err        bool
+	}{
+		{words: sixteen, bits: 30, expected: 8},
+		{words: sixteen, bits: 32, expected: 8},
+		{words: sixteen, bits: 32.5, expected: 9},
+		{words: sixteen, bits: 80, expected: 20},
+		{words: sixteen, capitalize: "random", bits: 80, expected: 16},
+		{words: sixteen, bits: 0, err: true},
+		{words: sixteen, bits: -1, err: true},
+		{words: sixteen, bits: math.NaN(), err: true},
+		{words: sixteen, bits: math.Inf(1), err: true},
+		{words: sixteen, bits: 1e300, err: true},
+		{words: sixteen, bits: 4 * MaxWords, expected: MaxWords},
+		{words: sixteen, bits: 4*MaxWords + 1, err: true},
+		{words: []string{"1"}, bits: 30, err: true},
+		{words: []string{"a"}, capitalize: "random", bits: 30, expected: 30},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			d := newDictionary(test.words)
+			d.SetCapitalize(test.capitalize)
+			actual, err := d.WordsForEntropy(test.bits)
+			switch {
+			case test.err && err == nil:
+				t.Errorf("expected an error, got %d words", actual)
+			case !test.err && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case actual != test.expected:
+				t.Errorf("expected %d words, got %d", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPassphraseForEntropy(t *testing.T) {
+	d := newDictionary(strings.Split("a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p", ","))
+	p, err := d.PassphraseForEntropy(50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != 13 {
+		t.Errorf("expected 13 words, got %d", len(p))
+	}
+
+	d.SetMinWordLength(2)
+	if p, err := d.PassphraseForEntropy(50); err == nil {
+		t.Errorf("expected an error, got %v", p)
+	}
+}
+
+func TestMinEntropy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		minEntropy float64
+		words      int
+		expected   float64
+	}{
+		{minEntropy: 30, words: 7, expected: 30},
+		{minEntropy: 30, words: 8},
+		{minEntropy: 60, words: 8, expected: 60},
+		{minEntropy: 60, words: 14, expected: 60},
+		{minEntropy: 60, words: 15},
+		{minEntropy: 0, words: 1},
+		{minEntropy: -5, words: 1},
+		{minEntropy: math.NaN(), words: 7, expected: DefaultMinEntropy},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			d := newDictionary(strings.Split("a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p", ","))
+			d.SetMinEntropy(test.minEntropy)
+			_, err := d.Passphrase(test.words)
+			if test.expected == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var entropyErr *EntropyError
+			if !errors.As(err, &entropyErr) {
+				t.Fatalf("expected an EntropyError, got %v", err)
+			}
+			if entropyErr.Required != test.expected {
+				t.Errorf("expected %0.1f required bits, got %0.1f", test.expected, entropyErr.Required)
+			}
+			if achieved := float64(4 * test.words); entropyErr.Achieved != achieved {
+				t.Errorf("expected %0.1f achieved bits, got %0.1f", achieved, entropyErr.Achieved)
+			}
+		})
+	}
+}
+
+func TestPassphraseEmpty(t *testing.T) {
+	// the empty dictionary is reported before the missing entropy
+	d := newDictionary([]string{"a", "b"})
+	d.SetMinWordLength(2)
+	if _, err := d.Passphrase(4); !errors.Is(err, ErrEmptyWordlist) {
+		t.Errorf("expected ErrEmptyWordlist, got %v", err)
+	}
+}
+
+func TestPassphraseUniform(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		words     []string
+		minLength int
+		maxLength int
+		expected  []string
+	}{
+		{
+			name:     "two words",
+			words:    []string{"ab", "cd"},
+			expected: []string{"ab", "cd"},
+		},
+		{
+			name:     "ten words",
+			words:    []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"},
+			expected: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"},
+		},
+		{
+			name:      "filtered",
+			words:     []string{"a", "b", "cc", "dd", "ee", "fff", "ggg", "hhhh"},
+			minLength: 2,
+			maxLength: 3,
+			expected:  []string{"cc", "dd", "ee", "fff", "ggg"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			d := newDictionary(test.words)
+			d.randReader = &hashReader{}
+			d.SetMinWordLength(test.minLength)
+			d.SetMaxWordLength(test.maxLength)
+
+			counts := make(map[string]int)
+			n := int(math.Ceil(DefaultMinEntropy / math.Log2(float64(d.Length()))))
+			for i := 0; i < 20000/n; i++ {
+				p, err := d.Passphrase(n)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, w := range p {
+					counts[w]++
+				}
+			}
+
+			observed := make([]int, len(test.expected))
+			for i, w := range test.expected {
+				observed[i] = counts[w]
+				delete(counts, w)
+			}
+			if len(counts) > 0 {
+				t.Errorf("chose words outside of the dictionary range: %v", counts)
+			}
+			assertUniform(t, observed)
+		})
+	}
+}
+
+func TestCapitalizeRandomUniform(t *testing.T) {
+	t.Parallel()
+	d := newDictionary(strings.Fields(strings.Repeat("able ", 1000)))
+	d.randReader = &hashReader{}
+	d.SetCapitalize("random")
+
+	// each of the 16 ways to capitalize "able" should be equally likely
+	observed := make([]int, 16)
+	for i := 0; i < 4000; i++ {
+		p, err := d.Passphrase(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range p {
+			pattern := 0
+			for j, c := range w {
+				if unicode.IsUpper(c) {
+					pattern |= 1 << j
+				}
+			}
+			observed[pattern]++
+		}
+	}
+	assertUniform(t, observed)
+}
+
+func TestCapitalizeLanguage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		word       string
+		tag        language.Tag
+		capitalize string
+		expected   string
+	}{
+		{"ñandú", language.Spanish, "all", "ÑANDÚ"},
+		{"ñandú", language.Spanish, "first", "Ñandú"},
+		{"ñandú", language.Spanish, "random", "ÑANDÚ"},
+		{"árbol", language.Spanish, "first", "Árbol"},
+		{"istanbul", language.Und, "all", "ISTANBUL"},
+		{"istanbul", language.Turkish, "all", "İSTANBUL"},
+		{"istanbul", language.Turkish, "first", "İstanbul"},
+		{"istanbul", language.Turkish, "random", "İSTANBUL"},
+		{"ijsland", language.Dutch, "first", "IJsland"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.tag.String()+"/"+test.capitalize+"/"+test.word, func(t *testing.T) {
+			d := newDictionary([]string{test.word})
+			d.SetCapitalize(test.capitalize)
+			d.SetLanguage(test.tag)
+			d.SetMinEntropy(0)
+			p, err := d.Passphrase(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p[0] != test.expected {
+				t.Errorf("expected '%s', got '%s'", test.expected, p[0])
+			}
+		})
+	}
+}
+
+func TestLoadDictionaryLanguage(t *testing.T) {
+	d, err := LoadDictionary("es_MX.UTF-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Language() != language.Spanish {
+		t.Errorf("expected %v, got %v", language.Spanish, d.Language())
+	}
+}
+
 func BenchmarkNewDictionaySorted(b *testing.B) {
 	data := make([]string, 5000)
 	length := 3
@@ -448,3 +1069,22 @@ func benchmarkNewDictionary(b *testing.B, words []string) {
 		NewDictionary(r)
 	}
 }
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	d := newDictionary(strings.Split("a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p", ","))
+	if err := d.Check(8); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var entropyErr *EntropyError
+	if err := d.Check(7); !errors.As(err, &entropyErr) {
+		t.Errorf("expected an EntropyError, got %v", err)
+	}
+	if err := d.Check(0); !errors.Is(err, ErrInvalidWordCount) {
+		t.Errorf("expected ErrInvalidWordCount, got %v", err)
+	}
+	d.SetMinWordLength(2)
+	if err := d.Check(8); !errors.Is(err, ErrEmptyWordlist) {
+		t.Errorf("expected ErrEmptyWordlist, got %v", err)
+	}
+}
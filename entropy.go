@@ -0,0 +1,30 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+// Entropy itemizes the bits of entropy in a passphrase by where they come from.
+// It assumes an attacker who knows the word list and every setting used to
+// generate the passphrase, so only random choices contribute.
+type Entropy struct {
+	Words          float64 // choosing each word from the dictionary
+	Capitalization float64 // randomly capitalizing letters
+	Separators     float64 // randomly choosing the separators between words
+	Digits         float64 // random digits added to the passphrase
+	Symbols        float64 // random symbols added to the passphrase
+}
+
+// Bits returns the total bits of entropy.
+func (e Entropy) Bits() float64 {
+	return e.Words + e.Capitalization + e.Separators + e.Digits + e.Symbols
+}
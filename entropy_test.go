@@ -0,0 +1,33 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import "testing"
+
+func TestEntropyBits(t *testing.T) {
+	tests := []struct {
+		entropy  Entropy
+		expected float64
+	}{
+		{Entropy{}, 0},
+		{Entropy{Words: 52}, 52},
+		{Entropy{Words: 52, Capitalization: 16}, 68},
+		{Entropy{Words: 1, Capitalization: 2, Separators: 3, Digits: 4, Symbols: 5}, 15},
+	}
+	for idx, test := range tests {
+		if actual := test.entropy.Bits(); actual != test.expected {
+			t.Errorf("%d: expected %0.1f bits, got %0.1f", idx+1, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"fmt"
+)
+
+// Generator generates passphrases with a fixed number of words from a
+// Dictionary. It keeps its own view of the dictionary, whose words and
+// settings cannot change once the Generator is created, so it is safe for
+// concurrent use: create one at startup and share it between goroutines.
+type Generator struct {
+	dict  *Dictionary
+	words int
+}
+
+// NewGenerator returns a Generator of n-word passphrases from the words of d,
+// configured with opts. Later changes to d do not affect the Generator. If n
+// is less than 1, then an error wrapping ErrInvalidWordCount is returned. An
+// error is also returned if d has no words within its word length limits, or
+// if the passphrases would have less than the minimum entropy.
+func NewGenerator(d *Dictionary, n int, opts ...Option) (*Generator, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidWordCount, n)
+	}
+	g := &Generator{dict: d.With(opts...), words: n}
+	if g.dict.Length() == 0 {
+		return nil, fmt.Errorf("%w: no words match the word length limits", ErrEmptyWordlist)
+	}
+	if bits := g.Entropy().Bits(); bits < g.dict.MinEntropy() {
+		return nil, &EntropyError{Achieved: bits, Required: g.dict.MinEntropy()}
+	}
+	return g, nil
+}
+
+// NewGeneratorForEntropy is like NewGenerator, but uses the fewest words that
+// give passphrases at least bits of entropy.
+func NewGeneratorForEntropy(d *Dictionary, bits float64, opts ...Option) (*Generator, error) {
+	n, err := d.With(opts...).WordsForEntropy(bits)
+	if err != nil {
+		return nil, err
+	}
+	return NewGenerator(d, n, opts...)
+}
+
+// Words returns the number of words in each passphrase.
+func (g *Generator) Words() int {
+	return g.words
+}
+
+// Entropy returns the entropy of each passphrase, itemized by source.
+func (g *Generator) Entropy() Entropy {
+	return g.dict.PassphraseEntropy(g.words)
+}
+
+// Passphrase returns a slice of randomly chosen words.
+func (g *Generator) Passphrase() ([]string, error) {
+	return g.dict.Passphrase(g.words)
+}
+
+// Generate returns a Passphrase of randomly chosen words.
+func (g *Generator) Generate() (Passphrase, error) {
+	return g.dict.Generate(g.words)
+}
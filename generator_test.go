@@ -0,0 +1,150 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewGenerator(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary(bytes.NewBufferString("a\nbb\nccc\ndddd\n"))
+	d.SetMinEntropy(0)
+	g, err := NewGenerator(d, 3, WithLengthRange(3, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Words() != 3 {
+		t.Errorf("expected 3 words, got %d", g.Words())
+	}
+	if g.Entropy().Bits() != 3 {
+		t.Errorf("expected 3 bits, got %0.1f", g.Entropy().Bits())
+	}
+
+	// changing the dictionary does not change the generator
+	d.SetMaxWordLength(1)
+	words, err := g.Passphrase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range words {
+		if len(w) < 3 {
+			t.Errorf("expected words of at least 3 letters, got %v", words)
+		}
+	}
+
+	if _, err := NewGenerator(d, 0); !errors.Is(err, ErrInvalidWordCount) {
+		t.Errorf("expected error %v, got %v", ErrInvalidWordCount, err)
+	}
+	if _, err := NewGenerator(d, 4, WithLengthRange(5, 0)); !errors.Is(err, ErrEmptyWordlist) {
+		t.Errorf("expected error %v, got %v", ErrEmptyWordlist, err)
+	}
+	var entropyErr *EntropyError
+	if _, err := NewGenerator(d, 4, WithMinEntropy(DefaultMinEntropy)); !errors.As(err, &entropyErr) {
+		t.Errorf("expected an EntropyError, got %v", err)
+	}
+}
+
+func TestNewGeneratorForEntropy(t *testing.T) {
+	t.Parallel()
+	d, err := LoadDictionary("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewGeneratorForEntropy(d, 60, WithCapitalize("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Words() != 5 {
+		t.Errorf("expected 5 words, got %d", g.Words())
+	}
+	if _, err := NewGeneratorForEntropy(d, 0); err == nil {
+		t.Error("expected an error for 0 bits")
+	}
+}
+
+// TestGeneratorConcurrent shares a Generator between goroutines, and is meant
+// to be run with -race.
+func TestGeneratorConcurrent(t *testing.T) {
+	t.Parallel()
+	d, err := LoadDictionary("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]Option{
+		"default": WithCapitalize("random"),
+		"seeded":  WithRandSource(NewSeededReader([]byte("correct horse"))),
+		"dice":    WithRandSource(NewDiceReader(strings.NewReader(strings.Repeat("3141526553", 2000)))),
+	}
+	for name, source := range sources {
+		source := source
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			g, err := NewGenerator(d, 4, source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var wg sync.WaitGroup
+			errs := make(chan error, 8)
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < 20; j++ {
+						words, err := g.Passphrase()
+						if err != nil {
+							errs <- err
+							return
+						}
+						if len(words) != 4 {
+							errs <- errors.New("expected 4 words")
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func BenchmarkGeneratorGenerate(b *testing.B) {
+	for _, capitalize := range []string{"none", "random"} {
+		capitalize := capitalize
+		b.Run(capitalize, func(b *testing.B) {
+			d, err := LoadDictionary("es", WithCapitalize(capitalize))
+			if err != nil {
+				b.Fatal(err)
+			}
+			g, err := NewGenerator(d, 6)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := g.Generate(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+)
+
+// ErrHealthCheck is returned when a random source fails a health check.
+var ErrHealthCheck = errors.New("random source failed a health check")
+
+const (
+	// healthAlpha is the probability of a healthy source failing a test at
+	// any given byte, as recommended by NIST SP 800-90B.
+	healthAlpha = 1.0 / (1 << 20)
+	// aptWindow is the number of bytes in each window of the adaptive
+	// proportion test.
+	aptWindow = 512
+)
+
+// HealthCheckedReader reads from a random source, and runs the continuous
+// health tests of NIST SP 800-90B, section 4.4, on each byte:
+//
+//   - the repetition count test fails if the same byte repeats too many
+//     times in a row, which catches a source that is stuck.
+//   - the adaptive proportion test fails if the first byte of a 512-byte
+//     window repeats too often within the window, which catches a source that
+//     has lost much of its entropy.
+//
+// When a test fails, the Read returns an error wrapping ErrHealthCheck and
+// discards the bytes it read. The tests then start over, so that a rare false
+// alarm does not stop a long-lived reader for good, while a source that is
+// really broken keeps failing them.
+// A HealthCheckedReader is safe for concurrent use, and only reads from the
+// source in one goroutine at a time.
+type HealthCheckedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+
+	rctCutoff int
+	last      byte
+	repeats   int
+
+	aptCutoff int
+	reference byte
+	matches   int
+	seen      int
+}
+
+// NewHealthCheckedReader returns a HealthCheckedReader of r, which is assumed
+// to have at least entropy bits of min-entropy per byte. The tests are tuned
+// so that a healthy source fails them with probability 2^-20 per byte.
+// Values of entropy outside of (0, 8] are taken to mean 8 bits, a source of
+// full entropy.
+func NewHealthCheckedReader(r io.Reader, entropy float64) *HealthCheckedReader {
+	if entropy <= 0 || entropy > 8 {
+		entropy = 8
+	}
+	return &HealthCheckedReader{
+		r:         r,
+		rctCutoff: rctCutoff(entropy),
+		aptCutoff: aptCutoff(aptWindow, entropy),
+	}
+}
+
+// rctCutoff returns the number of times a byte may repeat in a row before the
+// repetition count test fails.
+func rctCutoff(entropy float64) int {
+	return 1 + int(math.Ceil(-math.Log2(healthAlpha)/entropy))
+}
+
+// aptCutoff returns the number of times the first byte of a window may appear
+// in the window before the adaptive proportion test fails. This is the
+// smallest count that a healthy source exceeds with probability at most
+// healthAlpha.
+func aptCutoff(window int, entropy float64) int {
+	p := math.Exp2(-entropy)
+	var cdf float64
+	for k := 0; k < window; k++ {
+		cdf += binomial(window, k, p)
+		if cdf >= 1-healthAlpha {
+			return k + 1
+		}
+	}
+	return window
+}
+
+// binomial returns the probability of k successes in n trials, each with
+// probability p.
+func binomial(n, k int, p float64) float64 {
+	lnN, _ := math.Lgamma(float64(n + 1))
+	lnK, _ := math.Lgamma(float64(k + 1))
+	lnNK, _ := math.Lgamma(float64(n - k + 1))
+	return math.Exp(lnN - lnK - lnNK + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
+
+// Read reads from the source into p, and returns an error wrapping
+// ErrHealthCheck if the bytes read fail a health test. Later reads run the
+// tests from the start.
+func (h *HealthCheckedReader) Read(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n, err := h.r.Read(p)
+	for _, b := range p[:n] {
+		if err := h.check(b); err != nil {
+			h.reset()
+			return 0, err
+		}
+	}
+	return n, err
+}
+
+// reset starts the health tests over.
+func (h *HealthCheckedReader) reset() {
+	h.repeats, h.matches, h.seen = 0, 0, 0
+}
+
+// check runs the health tests on the next byte b.
+func (h *HealthCheckedReader) check(b byte) error {
+	if h.repeats > 0 && b == h.last {
+		h.repeats++
+		if h.repeats >= h.rctCutoff {
+			return fmt.Errorf("%w: repetition count test: 0x%02x repeated %d times in a row", ErrHealthCheck, b, h.repeats)
+		}
+	} else {
+		h.last = b
+		h.repeats = 1
+	}
+
+	if h.seen == 0 {
+		h.reference = b
+		h.matches = 0
+	}
+	if b == h.reference {
+		h.matches++
+		if h.matches >= h.aptCutoff {
+			return fmt.Errorf("%w: adaptive proportion test: 0x%02x seen %d times in %d bytes", ErrHealthCheck, b, h.matches, h.seen+1)
+		}
+	}
+	h.seen = (h.seen + 1) % aptWindow
+	return nil
+}
+
+// healthChecked returns r wrapped in health checks that assume full entropy,
+// unless it already is. DiceReaders are used as they are, since the words of
+// a Diceware word list are looked up by their rolls.
+func healthChecked(r io.Reader) io.Reader {
+	switch r.(type) {
+	case *HealthCheckedReader, *DiceReader:
+		return r
+	}
+	return NewHealthCheckedReader(r, 8)
+}
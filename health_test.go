@@ -0,0 +1,142 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestHealthCutoffs(t *testing.T) {
+	t.Parallel()
+	// the cutoffs for a 512-byte window from table 2 of NIST SP 800-90B
+	tests := []struct {
+		entropy     float64
+		expectedRCT int
+		expectedAPT int
+	}{
+		{0.5, 41, 410},
+		{1, 21, 311},
+		{2, 11, 177},
+		{4, 6, 62},
+		{8, 4, 13},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			if actual := rctCutoff(test.entropy); actual != test.expectedRCT {
+				t.Errorf("expected repetition count cutoff %d, got %d", test.expectedRCT, actual)
+			}
+			if actual := aptCutoff(aptWindow, test.entropy); actual != test.expectedAPT {
+				t.Errorf("expected adaptive proportion cutoff %d, got %d", test.expectedAPT, actual)
+			}
+		})
+	}
+}
+
+// alternatingReader returns b for every other byte, and a counter otherwise.
+type alternatingReader struct {
+	b       byte
+	counter byte
+}
+
+func (a *alternatingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		if i%2 == 0 {
+			p[i] = a.b
+		} else {
+			a.counter++
+			p[i] = a.counter
+		}
+	}
+	return len(p), nil
+}
+
+func TestHealthCheckedReader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		r        io.Reader
+		expected error
+	}{
+		{&hashReader{}, nil},
+		{constantReader(0), ErrHealthCheck},
+		{bytes.NewReader([]byte{1, 2, 3, 3, 3, 3}), ErrHealthCheck},
+		{&alternatingReader{b: 7}, ErrHealthCheck},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			h := NewHealthCheckedReader(test.r, 8)
+			p := make([]byte, 1<<16)
+			_, err := io.ReadFull(h, p)
+			if !errors.Is(err, test.expected) {
+				t.Fatalf("expected error %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestHealthCheckedReaderRecovers(t *testing.T) {
+	t.Parallel()
+	// a run of repeated bytes fails once, and the tests start over after it
+	h := NewHealthCheckedReader(io.MultiReader(bytes.NewReader([]byte{1, 2, 3, 3, 3, 3}), &hashReader{}), 8)
+	p := make([]byte, 1<<16)
+	if _, err := h.Read(p[:6]); !errors.Is(err, ErrHealthCheck) {
+		t.Fatalf("expected error %v, got %v", ErrHealthCheck, err)
+	}
+	if _, err := io.ReadFull(h, p); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	// a stuck source keeps failing
+	h = NewHealthCheckedReader(constantReader(0), 8)
+	for i := 0; i < 3; i++ {
+		if _, err := io.ReadFull(h, p); !errors.Is(err, ErrHealthCheck) {
+			t.Errorf("expected error %v, got %v", ErrHealthCheck, err)
+		}
+	}
+}
+
+func TestSetRandSource(t *testing.T) {
+	t.Parallel()
+	d := newDictionary([]string{"a", "b", "c", "d", "e", "f", "g", "h"})
+	d.SetMinEntropy(0)
+	d.SetRandSource(&hashReader{})
+	if _, err := d.Passphrase(4); err != nil {
+		t.Fatal(err)
+	}
+
+	d.SetRandSource(constantReader(0))
+	if _, err := d.Passphrase(4); !errors.Is(err, ErrHealthCheck) {
+		t.Errorf("expected error %v, got %v", ErrHealthCheck, err)
+	}
+
+	// health checked sources are not wrapped again
+	h := NewHealthCheckedReader(&hashReader{}, 4)
+	d.SetRandSource(h)
+	if d.randReader != h {
+		t.Errorf("expected %v, got %v", h, d.randReader)
+	}
+
+	m := NewMultiDictionary(d, newDictionary([]string{"x", "y"}))
+	m.SetMinEntropy(0)
+	m.SetPolicy("random")
+	m.SetRandSource(constantReader(1))
+	if _, err := m.Passphrase(4); !errors.Is(err, ErrHealthCheck) {
+		t.Errorf("expected error %v, got %v", ErrHealthCheck, err)
+	}
+}
@@ -17,30 +17,207 @@ package langs
 
 import (
 	"embed"
+	"errors"
 	"fmt"
-	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/wfscheper/xkcdpwd/internal/userinfo"
 	"golang.org/x/text/language"
 )
 
+// DefaultLanguage is the language used when no language is requested.
+var DefaultLanguage = language.English
+
+// ErrUnknownLanguage is returned when no word list matches the requested
+// language.
+var ErrUnknownLanguage = errors.New("unsupported language")
+
+const appName = "xkcdpwd"
+
 //go:embed languages
 var langs embed.FS
 
-var matcher = language.NewMatcher([]language.Tag{
-	language.English,
-})
+// Embedded is the source of the word lists embedded in xkcdpwd.
+var Embedded = Source{FS: langs, Dir: "languages"}
 
-func GetLanguage(lang string) ([]byte, error) {
-	tag, _ := language.MatchStrings(matcher, lang)
-	switch tag {
-	case language.English:
-		file, err := langs.Open("languages/en")
+var (
+	registry     *Registry
+	registryErr  error
+	registryOnce sync.Once
+)
+
+// Source is a directory of word lists in a file system. Each file in the
+// directory is named after the IETF language tag of the word list it contains.
+type Source struct {
+	FS       fs.FS
+	Dir      string
+	Path     string // the path of Dir shown in errors, Dir if empty
+	Optional bool   // skip the source if Dir cannot be read
+}
+
+// path returns the path of name, relative to Dir, shown in errors.
+func (s Source) path(name string) string {
+	if s.Path == "" {
+		return path.Join(s.Dir, name)
+	}
+	return filepath.Join(s.Path, filepath.FromSlash(name))
+}
+
+// DefaultSources returns the sources searched for word lists, in order of
+// precedence: the user's languages directory, the system-wide languages
+// directories, and finally the word lists embedded in xkcdpwd. The languages
+// directories are optional, and found with the environment read by getenv.
+func DefaultSources(getenv func(string) string) []Source {
+	var sources []Source
+	if dir, err := userinfo.DefaultLanguagesDir(appName, getenv); err == nil {
+		sources = append(sources, Source{FS: os.DirFS(dir), Dir: ".", Path: dir, Optional: true})
+	}
+	for _, dir := range userinfo.SystemLanguagesDirs(appName, getenv) {
+		sources = append(sources, Source{FS: os.DirFS(dir), Dir: ".", Path: dir, Optional: true})
+	}
+	return append(sources, Embedded)
+}
+
+type wordlist struct {
+	fsys fs.FS
+	name string
+	path string
+}
+
+// Registry maps language tags to the word lists found in one or more sources.
+type Registry struct {
+	files   map[language.Tag]wordlist
+	matcher language.Matcher
+	tags    []language.Tag
+}
+
+// NewRegistry returns a Registry of the word lists in sources. If more than
+// one source has a word list for a language, then the earliest source wins.
+// Sources whose directory does not exist, optional sources whose directory
+// cannot be read, and files whose names are not valid language tags, are
+// ignored.
+func NewRegistry(sources ...Source) (*Registry, error) {
+	r := &Registry{files: map[language.Tag]wordlist{}}
+	for _, source := range sources {
+		entries, err := fs.ReadDir(source.FS, source.Dir)
 		if err != nil {
-			return nil, err
+			if errors.Is(err, fs.ErrNotExist) || source.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("cannot read languages directory: %w", withPath(err, source.path(".")))
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			tag, err := language.Parse(entry.Name())
+			if err != nil {
+				continue
+			}
+			if _, ok := r.files[tag]; ok {
+				continue
+			}
+			r.files[tag] = wordlist{
+				fsys: source.FS,
+				name: path.Join(source.Dir, entry.Name()),
+				path: source.path(entry.Name()),
+			}
+			r.tags = append(r.tags, tag)
 		}
-		defer file.Close()
-		return io.ReadAll(file)
-	default:
-		return nil, fmt.Errorf("No language file found for %s", lang)
 	}
+	if len(r.tags) == 0 {
+		return nil, errors.New("no languages found")
+	}
+
+	// the first tag is the fallback for the matcher, so the default language
+	// goes first if we have it, and the rest are sorted for stable output
+	sort.Slice(r.tags, func(i, j int) bool {
+		switch {
+		case r.tags[i] == DefaultLanguage:
+			return true
+		case r.tags[j] == DefaultLanguage:
+			return false
+		default:
+			return r.tags[i].String() < r.tags[j].String()
+		}
+	})
+	r.matcher = language.NewMatcher(r.tags)
+	return r, nil
+}
+
+// DefaultRegistry returns the Registry of the DefaultSources in the
+// environment of the process. It is only built once.
+func DefaultRegistry() (*Registry, error) {
+	registryOnce.Do(func() {
+		registry, registryErr = NewRegistry(DefaultSources(os.Getenv)...)
+	})
+	return registry, registryErr
+}
+
+// Tags returns the language tags of the registered word lists.
+func (r *Registry) Tags() []language.Tag {
+	tags := make([]language.Tag, len(r.tags))
+	copy(tags, r.tags)
+	return tags
+}
+
+// Match returns the registered language tag that best matches lang. lang may
+// be an IETF language tag, an Accept-Language string, or a POSIX locale like
+// es_ES.UTF-8. An empty lang, or the C and POSIX locales, match the default
+// language.
+func (r *Registry) Match(lang string) (language.Tag, error) {
+	normalized := normalizeLocale(lang)
+	switch normalized {
+	case "", "C", "POSIX":
+		return r.tags[0], nil
+	}
+	tags, _, err := language.ParseAcceptLanguage(normalized)
+	if err != nil || len(tags) == 0 {
+		return language.Und, fmt.Errorf("%w '%s'", ErrUnknownLanguage, lang)
+	}
+	_, idx, confidence := r.matcher.Match(tags...)
+	if confidence == language.No {
+		return language.Und, fmt.Errorf("%w '%s'", ErrUnknownLanguage, lang)
+	}
+	return r.tags[idx], nil
+}
+
+// GetLanguage returns the language tag and contents of the word list that
+// best matches lang.
+func (r *Registry) GetLanguage(lang string) (language.Tag, []byte, error) {
+	tag, err := r.Match(lang)
+	if err != nil {
+		return language.Und, nil, err
+	}
+	file := r.files[tag]
+	data, err := fs.ReadFile(file.fsys, file.name)
+	if err != nil {
+		return language.Und, nil, withPath(err, file.path)
+	}
+	return tag, data, nil
+}
+
+// withPath returns err with its path replaced by path, so that the error names
+// the file on disk instead of the file in its file system.
+func withPath(err error, path string) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return &fs.PathError{Op: pathErr.Op, Path: path, Err: pathErr.Err}
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
+
+// normalizeLocale converts a POSIX locale such as es_ES.UTF-8@euro into an
+// IETF language tag the matcher understands.
+func normalizeLocale(lang string) string {
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	return strings.ReplaceAll(lang, "_", "-")
 }
@@ -0,0 +1,261 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package langs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/wfscheper/xkcdpwd/internal/userinfo"
+	"golang.org/x/text/language"
+)
+
+func TestSupported(t *testing.T) {
+	r, err := NewRegistry(Embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []language.Tag{language.English, language.Spanish}
+	if actual := r.Tags(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	fsys := fstest.MapFS{
+		"words/pt-BR":     {Data: []byte("palavra\n")},
+		"words/de":        {Data: []byte("wort\n")},
+		"words/en":        {Data: []byte("word\n")},
+		"words/README.md": {Data: []byte("not a language\n")},
+		"words/nested/fr": {Data: []byte("mot\n")},
+	}
+	r, err := NewRegistry(Source{FS: fsys, Dir: "words"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []language.Tag{language.English, language.German, language.BrazilianPortuguese}
+	if actual := r.Tags(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	if _, err := NewRegistry(Source{FS: fstest.MapFS{"words/README.md": {}}, Dir: "words"}); err == nil {
+		t.Error("expected an error for a directory without languages")
+	}
+}
+
+func TestNewRegistrySources(t *testing.T) {
+	user := fstest.MapFS{
+		"en": {Data: []byte("user\n")},
+		"de": {Data: []byte("wort\n")},
+	}
+	system := fstest.MapFS{
+		"en": {Data: []byte("system\n")},
+		"es": {Data: []byte("sistema\n")},
+		"fr": {Data: []byte("système\n")},
+	}
+	missing := Source{FS: fstest.MapFS{}, Dir: "missing"}
+	r, err := NewRegistry(Source{FS: user, Dir: "."}, missing, Source{FS: system, Dir: "."}, Embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []language.Tag{language.English, language.German, language.Spanish, language.French}
+	if actual := r.Tags(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	tests := []struct {
+		lang     string
+		expected string
+	}{
+		{"en", "user\n"},
+		{"de", "wort\n"},
+		{"es", "sistema\n"},
+		{"fr", "système\n"},
+	}
+	for _, test := range tests {
+		_, data, err := r.GetLanguage(test.lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("%s: expected '%s', got '%s'", test.lang, test.expected, data)
+		}
+	}
+}
+
+// unreadableFS is a file system whose files cannot be opened.
+type unreadableFS struct{ fs.FS }
+
+func (u unreadableFS) Open(name string) (fs.File, error) {
+	if name == "." {
+		return u.FS.Open(name)
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+func TestNewRegistryUnreadable(t *testing.T) {
+	notDir := fstest.MapFS{"languages": {Data: []byte("not a directory\n")}}
+	r, err := NewRegistry(Source{FS: notDir, Dir: "languages", Optional: true}, Embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []language.Tag{language.English, language.Spanish}
+	if actual := r.Tags(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	_, err = NewRegistry(Source{FS: notDir, Dir: "languages", Path: "/home/user/languages"}, Embedded)
+	if err == nil || !strings.Contains(err.Error(), "/home/user/languages") {
+		t.Errorf("expected an error naming /home/user/languages, got %v", err)
+	}
+
+	words := fstest.MapFS{"en": {Data: []byte("word\n")}}
+	r, err = NewRegistry(Source{FS: unreadableFS{words}, Dir: ".", Path: "/home/user/languages"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = r.GetLanguage("en")
+	if expected := filepath.Join("/home/user/languages", "en"); err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected an error naming %s, got %v", expected, err)
+	}
+}
+
+func TestDefaultSources(t *testing.T) {
+	home := t.TempDir()
+	env := map[string]string{
+		"HOME":            home,
+		"XDG_CONFIG_HOME": filepath.Join(home, ".config"),
+		"AppData":         filepath.Join(home, "AppData"),
+		"XDG_DATA_DIRS":   filepath.Join(home, "share"),
+		"ProgramData":     filepath.Join(home, "ProgramData"),
+	}
+	getenv := func(key string) string { return env[key] }
+	dir, err := userinfo.DefaultLanguagesDir(appName, getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "de"), []byte("wort\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	system := userinfo.SystemLanguagesDirs(appName, getenv)[0]
+	if err := os.MkdirAll(system, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(system, "fr"), []byte("mot\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRegistry(DefaultSources(getenv)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, data, err := r.GetLanguage("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != language.German || string(data) != "wort\n" {
+		t.Errorf("expected the word list in %s, got %v '%s'", dir, tag, data)
+	}
+	tag, data, err = r.GetLanguage("fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != language.French || string(data) != "mot\n" {
+		t.Errorf("expected the word list in %s, got %v '%s'", system, tag, data)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	r, err := NewRegistry(Embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		lang     string
+		expected language.Tag
+	}{
+		{"", language.English},
+		{"C", language.English},
+		{"POSIX", language.English},
+		{"en", language.English},
+		{"en-GB", language.English},
+		{"en_US.UTF-8", language.English},
+		{"es", language.Spanish},
+		{"es-MX", language.Spanish},
+		{"es_ES.UTF-8", language.Spanish},
+		{"es_ES@euro", language.Spanish},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.lang, func(t *testing.T) {
+			actual, err := r.Match(test.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMatchUnknown(t *testing.T) {
+	t.Parallel()
+	r, err := NewRegistry(Embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lang := range []string{"de", "fr-CA", "foo", "not a language"} {
+		lang := lang
+		t.Run(lang, func(t *testing.T) {
+			if _, err := r.Match(lang); !errors.Is(err, ErrUnknownLanguage) {
+				t.Errorf("expected ErrUnknownLanguage, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetLanguage(t *testing.T) {
+	r, err := NewRegistry(Embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, data, err := r.GetLanguage("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != language.Spanish {
+		t.Errorf("expected %v, got %v", language.Spanish, tag)
+	}
+	if len(data) == 0 {
+		t.Error("expected Spanish word list, got nothing")
+	}
+	_, en, err := r.GetLanguage("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(en) == string(data) {
+		t.Error("expected Spanish and English word lists to differ")
+	}
+}
@@ -29,10 +29,13 @@ type Case struct {
 	name        string
 	rootPath    string
 	Commands    [][]string `json:"commands"`
+	Config      string     `json:"config,omitempty"`
+	Env         []string   `json:"env,omitempty"`
 	Skip        bool       `json:"skip"`
 	Passphrases *uint      `json:"passphrases,omitempty"`
 	Words       *uint      `json:"words,omitempty"`
 	Separator   *string    `json:"separator,omitempty"`
+	Stdin       string     `json:"stdin,omitempty"`
 }
 
 // NewCase returns a Case.
@@ -58,6 +61,25 @@ func NewCase(t *testing.T, dir, name string) *Case {
 	return c
 }
 
+// InstallConfig copies the file named by the config field of the test
+// configuration, relative to the test directory, to path. It does nothing if
+// the field is empty.
+func (c *Case) InstallConfig(path string) {
+	if c.Config == "" {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(c.rootPath, c.Config))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		c.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
 // CompareOutput compares stdout to the contents of a stdout.txt file in the test directory.
 func (c *Case) CompareOutput(stdout string) {
 	expected, err := os.ReadFile(filepath.Join(c.rootPath, "stdout.txt"))
@@ -166,14 +188,47 @@ type Environment struct {
 	run    RunFunc
 }
 
-// NewEnvironment initializes the test Environment.
+// NewEnvironment initializes the test Environment. The locale is set to C, and
+// the home, config and system data directories to an empty temporary
+// directory, so that the output does not depend on the language, the
+// configuration or the word lists of the host.
 func NewEnvironment(t *testing.T, wd string, run RunFunc) *Environment {
-	return &Environment{
+	te := &Environment{
 		t:   t,
 		wd:  wd,
 		env: os.Environ(),
 		run: run,
 	}
+	home := t.TempDir()
+	te.Setenv("LANG=C", "HOME="+home, "XDG_CONFIG_HOME="+filepath.Join(home, ".config"),
+		"XDG_DATA_DIRS="+filepath.Join(home, "share"), "ProgramData="+filepath.Join(home, "ProgramData"))
+	return te
+}
+
+// Getenv returns the value of the environment variable key, or an empty
+// string if it is not set.
+func (te *Environment) Getenv(key string) string {
+	for i := len(te.env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(te.env[i], "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// Setenv sets the environment variables in kvs, each of the form key=value,
+// replacing any earlier values.
+func (te *Environment) Setenv(kvs ...string) {
+	for _, kv := range kvs {
+		key, _, _ := strings.Cut(kv, "=")
+		env := te.env[:0]
+		for _, e := range te.env {
+			if k, _, _ := strings.Cut(e, "="); k != key {
+				env = append(env, e)
+			}
+		}
+		te.env = append(env, kv)
+	}
 }
 
 // GetStdout returns the captures stdout.
@@ -186,8 +241,8 @@ func (te *Environment) GetStderr() string {
 	return te.stderr.String()
 }
 
-// Run runs the tests command with args.
-func (te *Environment) Run(args []string) error {
+// Run runs the tests command with args, reading stdin from stdin.
+func (te *Environment) Run(args []string, stdin string) error {
 	if *Verbose {
 		te.t.Logf("running testxkcdpwd %v", args)
 	}
@@ -195,7 +250,7 @@ func (te *Environment) Run(args []string) error {
 	te.stdout.Reset()
 	te.stderr.Reset()
 
-	status := te.run(prog, args, &te.stdout, &te.stderr, te.wd, te.env)
+	status := te.run(prog, args, strings.NewReader(stdin), &te.stdout, &te.stderr, te.wd, te.env)
 
 	if *Verbose {
 		if te.stdout.Len() > 0 {
@@ -209,4 +264,4 @@ func (te *Environment) Run(args []string) error {
 }
 
 // RunFunc is a function that runs a test.
-type RunFunc func(prog string, args []string, stdout, stderr io.Writer, dir string, env []string) error
+type RunFunc func(prog string, args []string, stdin io.Reader, stdout, stderr io.Writer, dir string, env []string) error
@@ -17,17 +17,90 @@
 package userinfo
 
 import (
-	"os"
+	"errors"
 	"path/filepath"
+	"runtime"
 )
 
+// UserConfigDir is os.UserConfigDir, but reads the environment with getenv.
+func UserConfigDir(getenv func(string) string) (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		dir := getenv("AppData")
+		if dir == "" {
+			return "", errors.New("%AppData% is not defined")
+		}
+		return dir, nil
+	case "darwin":
+		dir := getenv("HOME")
+		if dir == "" {
+			return "", errors.New("$HOME is not defined")
+		}
+		return filepath.Join(dir, "Library", "Application Support"), nil
+	default:
+		dir := getenv("XDG_CONFIG_HOME")
+		if dir == "" {
+			dir = getenv("HOME")
+			if dir == "" {
+				return "", errors.New("neither $XDG_CONFIG_HOME nor $HOME are defined")
+			}
+			return filepath.Join(dir, ".config"), nil
+		}
+		if !filepath.IsAbs(dir) {
+			return "", errors.New("path in $XDG_CONFIG_HOME is relative")
+		}
+		return dir, nil
+	}
+}
+
 // DefaultConfigFile returns the path to default location for a config file,
-// based on the underlying OS.
-func DefaultConfigFile(appName string) (string, error) {
-	configDir, err := os.UserConfigDir()
+// based on the underlying OS and the environment read by getenv.
+func DefaultConfigFile(appName string, getenv func(string) string) (string, error) {
+	configDir, err := UserConfigDir(getenv)
 	if err != nil {
 		return "", err
 	}
 
 	return filepath.Join(configDir, appName, appName+".conf"), nil
 }
+
+// DefaultLanguagesDir returns the path to the directory of the user's word
+// lists, which lives next to the default config file, reading the environment
+// with getenv.
+func DefaultLanguagesDir(appName string, getenv func(string) string) (string, error) {
+	configDir, err := UserConfigDir(getenv)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, appName, "languages"), nil
+}
+
+// SystemLanguagesDirs returns the paths to the directories of word lists
+// shared by all users, in order of precedence, based on the underlying OS and
+// the environment read by getenv. Outside of Windows, they are found in
+// $XDG_DATA_DIRS if it is set.
+func SystemLanguagesDirs(appName string, getenv func(string) string) []string {
+	if runtime.GOOS == "windows" {
+		programData := getenv("ProgramData")
+		if programData == "" {
+			return nil
+		}
+		return []string{filepath.Join(programData, appName, "languages")}
+	}
+	dataDirs := getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		if runtime.GOOS == "darwin" {
+			return []string{filepath.Join("/Library/Application Support", appName, "languages")}
+		}
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	var dirs []string
+	for _, dir := range filepath.SplitList(dataDirs) {
+		// relative paths in $XDG_DATA_DIRS are invalid, and are ignored
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, filepath.Join(dir, appName, "languages"))
+		}
+	}
+	return dirs
+}
@@ -0,0 +1,90 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userinfo
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestUserConfigDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("the XDG base directories are not used on %s", runtime.GOOS)
+	}
+	tests := []struct {
+		env      map[string]string
+		expected string
+	}{
+		{map[string]string{"XDG_CONFIG_HOME": "/xdg", "HOME": "/home/user"}, "/xdg"},
+		{map[string]string{"HOME": "/home/user"}, "/home/user/.config"},
+		{map[string]string{"XDG_CONFIG_HOME": "xdg", "HOME": "/home/user"}, ""},
+		{map[string]string{}, ""},
+	}
+	for _, test := range tests {
+		dir, err := UserConfigDir(func(key string) string { return test.env[key] })
+		if test.expected == "" {
+			if err == nil {
+				t.Errorf("%v: expected an error, got '%s'", test.env, dir)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.env, err)
+		} else if dir != test.expected {
+			t.Errorf("%v: expected '%s', got '%s'", test.env, test.expected, dir)
+		}
+	}
+}
+
+func TestSystemLanguagesDirs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("the XDG base directories are not used on %s", runtime.GOOS)
+	}
+	tests := []struct {
+		env      map[string]string
+		expected []string
+	}{
+		{map[string]string{}, []string{"/usr/local/share/app/languages", "/usr/share/app/languages"}},
+		{map[string]string{"XDG_DATA_DIRS": "/a:/b"}, []string{"/a/app/languages", "/b/app/languages"}},
+		{map[string]string{"XDG_DATA_DIRS": "a:/b"}, []string{"/b/app/languages"}},
+	}
+	for _, test := range tests {
+		actual := SystemLanguagesDirs("app", func(key string) string { return test.env[key] })
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%v: expected %v, got %v", test.env, test.expected, actual)
+		}
+	}
+}
+
+func TestDefaultConfigFile(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skipf("the XDG base directories are not used on %s", runtime.GOOS)
+	}
+	env := map[string]string{"XDG_CONFIG_HOME": "/xdg", "HOME": "/home/user"}
+	getenv := func(key string) string { return env[key] }
+	file, err := DefaultConfigFile("app", getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := DefaultLanguagesDir("app", getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the config file and the languages directory are found in the same
+	// environment
+	if file != "/xdg/app/app.conf" || dir != "/xdg/app/languages" {
+		t.Errorf("expected /xdg/app/app.conf and /xdg/app/languages, got %s and %s", file, dir)
+	}
+}
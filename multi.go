@@ -0,0 +1,428 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+
+	"golang.org/x/text/language"
+)
+
+// MultiDictionary generates passphrases that draw each word from one of
+// several dictionaries, such as one Spanish word followed by one English word.
+// Each word is chosen and capitalized according to the settings of the
+// dictionary it is drawn from.
+type MultiDictionary struct {
+	dicts      []*Dictionary
+	mixWords   float64 // the bits of a word for the random policy
+	mixCaps    float64 // the bits of its capitalization for the random policy
+	minEntropy float64
+	padding    Padding
+	pattern    []int
+	policy     string
+	randReader io.Reader
+	separator  string
+	separators randomSeparators
+}
+
+// NewMultiDictionary returns a MultiDictionary of dicts, using the
+// round-robin policy. It keeps its own copies of dicts, so later changes to
+// dicts do not affect it, and changing its random source does not affect
+// dicts.
+func NewMultiDictionary(dicts ...*Dictionary) *MultiDictionary {
+	copies := make([]*Dictionary, len(dicts))
+	for i, d := range dicts {
+		copies[i] = d.With()
+	}
+	m := &MultiDictionary{
+		dicts:      copies,
+		minEntropy: DefaultMinEntropy,
+		policy:     "round-robin",
+		randReader: rand.Reader,
+		separator:  " ",
+		separators: newRandomSeparators("", "gap"),
+	}
+	// the copies do not change, so neither does the entropy of the random
+	// policy
+	m.mixWords, m.mixCaps = m.mixtureEntropy()
+	return m
+}
+
+// Policy returns the current selection policy.
+func (m *MultiDictionary) Policy() string {
+	return m.policy
+}
+
+// SetPolicy sets how the dictionary of each word is chosen. The round-robin
+// policy cycles through the dictionaries in order, and the random policy
+// chooses a dictionary at random for each word. If the string passed in is
+// not a recognized policy, then 'round-robin' is used. Use SetPattern for the
+// explicit policy.
+func (m *MultiDictionary) SetPolicy(s string) {
+	switch s {
+	case "random":
+		m.policy = s
+	default:
+		m.policy = "round-robin"
+	}
+	m.pattern = nil
+}
+
+// Pattern returns a copy of the dictionary indexes of the explicit policy.
+func (m *MultiDictionary) Pattern() []int {
+	if m.pattern == nil {
+		return nil
+	}
+	return append([]int(nil), m.pattern...)
+}
+
+// SetPattern sets the explicit policy, where the i-th word of a passphrase is
+// drawn from the dictionary at index pattern[i]. The pattern repeats for
+// passphrases with more words than pattern. Later changes to pattern do not
+// affect m.
+func (m *MultiDictionary) SetPattern(pattern ...int) error {
+	if len(pattern) == 0 {
+		return fmt.Errorf("pattern must not be empty")
+	}
+	for _, idx := range pattern {
+		if idx < 0 || idx >= len(m.dicts) {
+			return fmt.Errorf("pattern index %d is out of range [0, %d)", idx, len(m.dicts))
+		}
+	}
+	m.policy = "explicit"
+	m.pattern = append([]int(nil), pattern...)
+	return nil
+}
+
+// SetRandSource sets the source of the random bytes used to choose the
+// dictionaries, and the words from them, which is crypto/rand.Reader by
+// default. As with Dictionary.SetRandSource, the source is health checked.
+func (m *MultiDictionary) SetRandSource(r io.Reader) {
+	r = healthChecked(r)
+	m.randReader = r
+	for _, d := range m.dicts {
+		d.SetRandSource(r)
+	}
+}
+
+// Separator returns the separator between the words of a passphrase.
+func (m *MultiDictionary) Separator() string {
+	return m.separator
+}
+
+// SetSeparator sets the separator between the words of a passphrase, which is
+// a space by default.
+func (m *MultiDictionary) SetSeparator(s string) {
+	m.separator = s
+}
+
+// SeparatorSet returns the characters that separators are chosen from at
+// random, or an empty string if the separator is fixed.
+func (m *MultiDictionary) SeparatorSet() string {
+	return m.separators.set
+}
+
+// SetSeparatorSet sets the characters that separators are chosen from at
+// random, like Dictionary.SetSeparatorSet.
+func (m *MultiDictionary) SetSeparatorSet(set string) {
+	m.separators = newRandomSeparators(set, m.separators.policy)
+}
+
+// SeparatorPolicy returns how often random separators are chosen.
+func (m *MultiDictionary) SeparatorPolicy() string {
+	return m.separators.policy
+}
+
+// SetSeparatorPolicy sets how often random separators are chosen, like
+// Dictionary.SetSeparatorPolicy.
+func (m *MultiDictionary) SetSeparatorPolicy(s string) {
+	m.separators = newRandomSeparators(m.separators.set, s)
+}
+
+// Padding returns the digits and symbols added to a passphrase.
+func (m *MultiDictionary) Padding() Padding {
+	return m.padding
+}
+
+// SetPadding sets the digits and symbols added to a passphrase, which is none
+// by default. The padding of the dictionaries is not used.
+func (m *MultiDictionary) SetPadding(pad Padding) {
+	m.padding = pad.normalize()
+}
+
+// MinEntropy returns the minimum number of bits of entropy a passphrase must
+// have.
+func (m *MultiDictionary) MinEntropy() float64 {
+	return m.minEntropy
+}
+
+// SetMinEntropy sets the minimum number of bits of entropy a passphrase must
+// have, like Dictionary.SetMinEntropy.
+func (m *MultiDictionary) SetMinEntropy(bits float64) {
+	m.minEntropy = minEntropy(bits)
+}
+
+// Length returns the number of distinct words in all of the dictionaries.
+func (m *MultiDictionary) Length() int {
+	seen := map[string]bool{}
+	for _, d := range m.dicts {
+		for _, w := range d.words[d.start:d.stop] {
+			seen[w] = true
+		}
+	}
+	return len(seen)
+}
+
+// cycle returns the number of words after which the choice of dictionaries
+// repeats.
+func (m *MultiDictionary) cycle() int {
+	switch m.policy {
+	case "explicit":
+		return len(m.pattern)
+	case "random":
+		return 1
+	default:
+		return len(m.dicts)
+	}
+}
+
+// Entropy returns the number of bits of entropy of an n-word passphrase.
+func (m *MultiDictionary) Entropy(n int) float64 {
+	return m.PassphraseEntropy(n).Bits()
+}
+
+// PassphraseEntropy returns the entropy of an n-word passphrase, itemized by
+// source.
+func (m *MultiDictionary) PassphraseEntropy(n int) Entropy {
+	var entropy Entropy
+	if len(m.dicts) == 0 {
+		return entropy
+	}
+	entropy.Digits, entropy.Symbols = m.padding.entropy()
+	entropy.Separators = m.separators.entropy(m.padding.gaps(n))
+	if m.policy == "random" {
+		entropy.Words = float64(n) * m.mixWords
+		entropy.Capitalization = float64(n) * m.mixCaps
+		return entropy
+	}
+	for i := 0; i < n; i++ {
+		e := m.dicts[m.dictIndex(i)].PassphraseEntropy(1)
+		entropy.Words += e.Words
+		entropy.Capitalization += e.Capitalization
+	}
+	return entropy
+}
+
+// mixtureEntropy returns the bits of entropy of a word, and of its
+// capitalization, when the dictionary is chosen at random. When the
+// dictionaries differ in size, the words of the smaller ones are more likely
+// to be chosen, as are words that are in more than one dictionary. The bits
+// are those of the most likely word, rather than the average, so that a small
+// dictionary cannot make weak passphrases look strong. Likewise, the
+// capitalization is that of the dictionary that adds the least.
+func (m *MultiDictionary) mixtureEntropy() (float64, float64) {
+	k := float64(len(m.dicts))
+	probabilities := map[string]float64{}
+	capitalization := -1.0
+	for _, d := range m.dicts {
+		if d.Length() == 0 {
+			continue
+		}
+		p := 1 / (k * float64(d.Length()))
+		for _, w := range d.words[d.start:d.stop] {
+			probabilities[w] += p
+		}
+		if capitalization < 0 || d.capEntropy < capitalization {
+			capitalization = d.capEntropy
+		}
+	}
+	if len(probabilities) == 0 {
+		return 0, 0
+	}
+	var most float64
+	for _, p := range probabilities {
+		most = math.Max(most, p)
+	}
+	return -math.Log2(most), capitalization
+}
+
+// WordsForEntropy returns the number of words a passphrase needs to have at
+// least bits of entropy. An error is returned if bits is not a finite number
+// greater than 0, or if the dictionaries cannot reach bits of entropy with at
+// most MaxWords words. If any dictionary has no words within its word length
+// limits, then the error wraps ErrEmptyWordlist.
+func (m *MultiDictionary) WordsForEntropy(bits float64) (int, error) {
+	if err := checkEntropy(bits); err != nil {
+		return 0, err
+	}
+	if err := m.checkWords(); err != nil {
+		return 0, err
+	}
+	// the first cycle through the dictionaries, and the word after it, are
+	// checked one by one, since the first word adds no separator
+	c := m.cycle()
+	for n := 1; n <= c+1 && n <= MaxWords; n++ {
+		if m.Entropy(n) >= bits {
+			return n, nil
+		}
+	}
+	// after that, each cycle adds the same entropy
+	perCycle := m.Entropy(2*c+1) - m.Entropy(c+1)
+	if perCycle <= 0 {
+		return 0, fmt.Errorf("dictionaries of %d words cannot reach %0.1f bits of entropy", m.Length(), bits)
+	}
+	// a passphrase of c+1+r+k*c words has k more cycles than one of c+1+r
+	// words, so find the fewest cycles each offset r needs
+	best := -1
+	for r := 0; r < c; r++ {
+		base := c + 1 + r
+		cycles := math.Max(math.Ceil((bits-m.Entropy(base))/perCycle), 0)
+		if cycles > float64(MaxWords/c+1) {
+			continue
+		}
+		n := base + int(cycles)*c
+		// the sums of Entropy may round differently than the estimate
+		for n <= MaxWords && m.Entropy(n) < bits {
+			n += c
+		}
+		if n <= MaxWords && (best < 0 || n < best) {
+			best = n
+		}
+	}
+	if best < 0 {
+		return 0, tooManyWords(bits)
+	}
+	return best, nil
+}
+
+// Passphrase returns a slice of n randomly chosen words. If the passphrase
+// would have less than the minimum entropy, then an *EntropyError is returned.
+func (m *MultiDictionary) Passphrase(n int) ([]string, error) {
+	words, _, err := m.PassphraseWithEntropy(n)
+	return words, err
+}
+
+// PassphraseWithEntropy is like Passphrase, but also returns the entropy of
+// the passphrase.
+func (m *MultiDictionary) PassphraseWithEntropy(n int) ([]string, Entropy, error) {
+	p, err := m.Generate(n)
+	return p.Words, p.Entropy, err
+}
+
+// Generate returns a Passphrase of n randomly chosen words, like
+// Dictionary.Generate. The language and each of the settings of the
+// Passphrase are those of the dictionaries if they all agree, and zero values
+// otherwise.
+func (m *MultiDictionary) Generate(n int) (Passphrase, error) {
+	if n < 1 {
+		return Passphrase{}, fmt.Errorf("%w, got %d", ErrInvalidWordCount, n)
+	}
+	p := Passphrase{
+		Separator: m.separator,
+		Language:  language.Und,
+		Entropy:   m.PassphraseEntropy(n),
+	}
+	for i, d := range m.dicts {
+		if i == 0 {
+			p.Language, p.Settings = d.language, d.Settings()
+			continue
+		}
+		if p.Language != d.language {
+			p.Language = language.Und
+		}
+		p.Settings = p.Settings.common(d.Settings())
+	}
+	if err := m.Check(n); err != nil {
+		return p, err
+	}
+
+	c := make([]casers, len(m.dicts))
+	for i, d := range m.dicts {
+		c[i] = d.newCasers()
+	}
+	words := make([]string, n)
+	indexes := make([]int, n)
+	dicts := make([]int, n)
+	for i := 0; i < n; i++ {
+		idx := m.dictIndex(i)
+		if m.policy == "random" {
+			choice, err := rand.Int(m.randReader, big.NewInt(int64(len(m.dicts))))
+			if err != nil {
+				return p, fmt.Errorf("cannot generate random words: %w", err)
+			}
+			idx = int(choice.Int64())
+		}
+		word, wordIdx, err := m.dicts[idx].chooseWord(c[idx])
+		if err != nil {
+			return p, err
+		}
+		words[i] = word
+		indexes[i] = wordIdx
+		dicts[i] = idx
+	}
+	before, after, err := m.padding.generate(m.randReader)
+	if err != nil {
+		return p, err
+	}
+	p.Words = words
+	p.Indexes = indexes
+	p.Dictionaries = dicts
+	p.Before, p.After, p.Placement = before, after, m.padding.Placement
+	if err := m.separators.choose(&p, m.randReader); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// Check returns the error Generate would return for an n-word passphrase
+// before choosing any words, or nil if there is none.
+func (m *MultiDictionary) Check(n int) error {
+	if n < 1 {
+		return fmt.Errorf("%w, got %d", ErrInvalidWordCount, n)
+	}
+	if err := m.checkWords(); err != nil {
+		return err
+	}
+	if bits := m.Entropy(n); bits < m.minEntropy {
+		return &EntropyError{Achieved: bits, Required: m.minEntropy}
+	}
+	return nil
+}
+
+// checkWords returns an error wrapping ErrEmptyWordlist if there are no
+// dictionaries, or if any of them has no words within its word length limits.
+func (m *MultiDictionary) checkWords() error {
+	if len(m.dicts) == 0 {
+		return ErrEmptyWordlist
+	}
+	for _, d := range m.dicts {
+		if d.Length() == 0 {
+			return fmt.Errorf("%w: no words match the word length limits", ErrEmptyWordlist)
+		}
+	}
+	return nil
+}
+
+// dictIndex returns the index of the dictionary the i-th word is drawn from,
+// for the round-robin and explicit policies.
+func (m *MultiDictionary) dictIndex(i int) int {
+	if m.policy == "explicit" {
+		return m.pattern[i%len(m.pattern)]
+	}
+	return i % len(m.dicts)
+}
@@ -0,0 +1,287 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newMultiDictionary returns a MultiDictionary of a 4-word dictionary of
+// a-words and an 8-word dictionary of b-words, without a minimum entropy.
+func newMultiDictionary() *MultiDictionary {
+	a := newDictionary([]string{"a1", "a2", "a3", "a4"})
+	b := newDictionary([]string{"b1", "b2", "b3", "b4", "b5", "b6", "b7", "b8"})
+	a.randReader = &hashReader{}
+	b.randReader = &hashReader{counter: 1 << 32}
+	m := NewMultiDictionary(a, b)
+	m.SetMinEntropy(0)
+	return m
+}
+
+// sources returns the first letter of each word, which names its dictionary.
+func sources(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		b.WriteString(w[:1])
+	}
+	return b.String()
+}
+
+func TestMultiDictionaryPolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		policy          string
+		pattern         []int
+		expected        string
+		expectedEntropy float64
+	}{
+		{policy: "round-robin", expected: "ababa", expectedEntropy: 12},
+		{policy: "foo", expected: "ababa", expectedEntropy: 12},
+		{pattern: []int{1, 0, 0}, expected: "baaba", expectedEntropy: 12},
+		{pattern: []int{1}, expected: "bbbbb", expectedEntropy: 15},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			m := newMultiDictionary()
+			if test.pattern != nil {
+				if err := m.SetPattern(test.pattern...); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				m.SetPolicy(test.policy)
+			}
+			words, entropy, err := m.PassphraseWithEntropy(5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := sources(words); actual != test.expected {
+				t.Errorf("expected words from %s, got %v", test.expected, words)
+			}
+			if entropy.Bits() != test.expectedEntropy {
+				t.Errorf("expected %0.1f bits, got %0.1f", test.expectedEntropy, entropy.Bits())
+			}
+		})
+	}
+}
+
+func TestMultiDictionaryPatternCopy(t *testing.T) {
+	t.Parallel()
+	m := newMultiDictionary()
+	pattern := []int{1, 0}
+	if err := m.SetPattern(pattern...); err != nil {
+		t.Fatal(err)
+	}
+	pattern[0] = 5
+	m.Pattern()[1] = 5
+	if actual := m.Pattern(); !reflect.DeepEqual(actual, []int{1, 0}) {
+		t.Errorf("expected [1 0], got %v", actual)
+	}
+	p, err := m.Generate(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := sources(p.Words); actual != "bab" || !reflect.DeepEqual(p.Dictionaries, []int{1, 0, 1}) {
+		t.Errorf("expected words from bab, got %v from %v", p.Words, p.Dictionaries)
+	}
+}
+
+func TestMultiDictionaryRandom(t *testing.T) {
+	t.Parallel()
+	m := newMultiDictionary()
+	m.SetPolicy("random")
+	m.randReader = &hashReader{counter: 2 << 32}
+	if m.Policy() != "random" {
+		t.Fatalf("expected random policy, got %s", m.Policy())
+	}
+
+	// each dictionary is equally likely, and each word equally likely
+	// within its dictionary
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		words, err := m.Passphrase(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range words {
+			counts[w]++
+		}
+	}
+	var a, b int
+	var aWords, bWords []int
+	for w, count := range counts {
+		switch w[0] {
+		case 'a':
+			a += count
+			aWords = append(aWords, count)
+		case 'b':
+			b += count
+			bWords = append(bWords, count)
+		}
+	}
+	assertUniform(t, []int{a, b})
+	assertUniform(t, aWords)
+	assertUniform(t, bWords)
+
+	// each word is recorded with the dictionary it was drawn from
+	p, err := m.Generate(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Dictionaries) != 8 {
+		t.Fatalf("expected 8 dictionaries, got %v", p.Dictionaries)
+	}
+	for i, w := range p.Words {
+		if actual := m.dicts[p.Dictionaries[i]].Word(p.Indexes[i]); actual != w {
+			t.Errorf("expected %s at index %d of dictionary %d, got %s", w, p.Indexes[i], p.Dictionaries[i], actual)
+		}
+	}
+
+	// the a-words are the most likely, at 1/2 × 1/4 each
+	if actual := m.Entropy(2); math.Abs(actual-6) > 1e-9 {
+		t.Errorf("expected 6 bits, got %f", actual)
+	}
+}
+
+func TestMultiDictionaryOverlap(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b     []string
+		expected float64
+	}{
+		// the same words from either dictionary are only 1 bit
+		{[]string{"x", "y"}, []string{"x", "y"}, 1},
+		{[]string{"w", "x"}, []string{"y", "z"}, 2},
+		// x is chosen half of the time, y and z a quarter of the time each
+		{[]string{"x"}, []string{"y", "z"}, 1},
+		{[]string{"x", "y"}, []string{"y", "z"}, 1},
+		// yes and no are chosen a quarter of the time each, however big
+		// the other dictionary is
+		{[]string{"yes", "no"}, strings.Split("a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p", ","), 2},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			m := NewMultiDictionary(newDictionary(test.a), newDictionary(test.b))
+			m.SetPolicy("random")
+			if actual := m.Entropy(1); math.Abs(actual-test.expected) > 1e-9 {
+				t.Errorf("expected %f bits, got %f", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMultiDictionaryWordsForEntropy(t *testing.T) {
+	t.Parallel()
+	m := newMultiDictionary()
+	tests := []struct {
+		bits     float64
+		expected int
+	}{
+		{1, 1},
+		{2, 1},
+		{5, 2},
+		{6, 3},
+		{30, 12},
+	}
+	for _, test := range tests {
+		actual, err := m.WordsForEntropy(test.bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != test.expected {
+			t.Errorf("%0.1f bits: expected %d words, got %d", test.bits, test.expected, actual)
+		}
+	}
+
+	for _, bits := range []float64{0, math.NaN(), math.Inf(1), 1e300} {
+		if n, err := m.WordsForEntropy(bits); err == nil {
+			t.Errorf("%0.1f bits: expected an error, got %d words", bits, n)
+		}
+	}
+
+	// the closed form agrees with counting the words one by one
+	if err := m.SetPattern(0, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, bits := range []float64{1, 7, 100, 1234.5} {
+		expected := 1
+		for m.Entropy(expected) < bits {
+			expected++
+		}
+		actual, err := m.WordsForEntropy(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("%0.1f bits: expected %d words, got %d", bits, expected, actual)
+		}
+	}
+
+	m = NewMultiDictionary(newDictionary([]string{"a"}), newDictionary([]string{"b"}))
+	if n, err := m.WordsForEntropy(30); err == nil {
+		t.Errorf("expected an error, got %d words", n)
+	}
+}
+
+func TestMultiDictionaryErrors(t *testing.T) {
+	t.Parallel()
+	m := newMultiDictionary()
+	for _, pattern := range [][]int{{}, {2}, {0, -1}} {
+		if err := m.SetPattern(pattern...); err == nil {
+			t.Errorf("expected an error for pattern %v", pattern)
+		}
+	}
+
+	for _, bits := range []float64{DefaultMinEntropy, math.NaN()} {
+		m.SetMinEntropy(bits)
+		if _, err := m.Passphrase(4); err == nil {
+			t.Errorf("%0.1f bits: expected an EntropyError", bits)
+		}
+	}
+
+	m = NewMultiDictionary(newDictionary([]string{"a", "b"}), newDictionary([]string{"cc", "dd"}).With(WithLengthRange(3, 0)))
+	if _, err := m.Passphrase(4); !errors.Is(err, ErrEmptyWordlist) {
+		t.Errorf("expected ErrEmptyWordlist, got %v", err)
+	}
+}
+
+func TestMultiDictionaryCopies(t *testing.T) {
+	t.Parallel()
+	a := newDictionary([]string{"a1", "a2"})
+	b := newDictionary([]string{"b1", "b2"})
+	m := NewMultiDictionary(a, b)
+	m.SetMinEntropy(0)
+	m.SetRandSource(constantReader(0))
+	for _, d := range []*Dictionary{a, b} {
+		if _, ok := d.randReader.(*HealthCheckedReader); ok {
+			t.Errorf("expected the random source of %v to be unchanged", d.words)
+		}
+	}
+
+	// changes to the dictionaries do not affect m
+	a.SetCapitalize("all")
+	words, err := m.Passphrase(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words[0] != "a1" {
+		t.Errorf("expected a1, got %s", words[0])
+	}
+}
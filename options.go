@@ -0,0 +1,109 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"io"
+
+	"golang.org/x/text/language"
+)
+
+// Option configures a Dictionary. Options are passed to NewDictionary and the
+// other constructors, or to With.
+type Option func(*Dictionary)
+
+// WithASCII sets whether the words are folded to ASCII, like SetASCII.
+func WithASCII(ascii bool) Option {
+	return func(d *Dictionary) {
+		d.SetASCII(ascii)
+	}
+}
+
+// WithCapitalize sets the capitalization strategy, like SetCapitalize.
+func WithCapitalize(s string) Option {
+	return func(d *Dictionary) {
+		d.SetCapitalize(s)
+	}
+}
+
+// WithLanguage sets the language of the words, like SetLanguage.
+func WithLanguage(tag language.Tag) Option {
+	return func(d *Dictionary) {
+		d.SetLanguage(tag)
+	}
+}
+
+// WithLengthRange limits the words to those from min to max characters long,
+// like SetMinWordLength and SetMaxWordLength.
+func WithLengthRange(min, max int) Option {
+	return func(d *Dictionary) {
+		d.SetMinWordLength(min)
+		d.SetMaxWordLength(max)
+	}
+}
+
+// WithMinEntropy sets the minimum bits of entropy of a passphrase, like
+// SetMinEntropy.
+func WithMinEntropy(bits float64) Option {
+	return func(d *Dictionary) {
+		d.SetMinEntropy(bits)
+	}
+}
+
+// WithPadding sets the digits and symbols added to a passphrase, like
+// SetPadding.
+func WithPadding(pad Padding) Option {
+	return func(d *Dictionary) {
+		d.SetPadding(pad)
+	}
+}
+
+// WithRandSource sets the source of random bytes, like SetRandSource.
+func WithRandSource(r io.Reader) Option {
+	return func(d *Dictionary) {
+		d.SetRandSource(r)
+	}
+}
+
+// WithSeparator sets the separator between words, like SetSeparator.
+func WithSeparator(s string) Option {
+	return func(d *Dictionary) {
+		d.SetSeparator(s)
+	}
+}
+
+// WithSeparatorSet sets the characters that separators are chosen from at
+// random, and how often they are chosen, like SetSeparatorSet and
+// SetSeparatorPolicy.
+func WithSeparatorSet(set, policy string) Option {
+	return func(d *Dictionary) {
+		d.SetSeparatorSet(set)
+		d.SetSeparatorPolicy(policy)
+	}
+}
+
+// With returns a copy of the dictionary configured with opts. The copy shares
+// the word list of d, so it is cheap to make one for each configuration
+// needed, and neither d nor the copy are affected by changes to the other.
+func (d *Dictionary) With(opts ...Option) *Dictionary {
+	c := *d
+	c.apply(opts)
+	return &c
+}
+
+func (d *Dictionary) apply(opts []Option) {
+	for _, opt := range opts {
+		opt(d)
+	}
+}
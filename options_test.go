@@ -0,0 +1,121 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestNewDictionaryOptions(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary(bytes.NewBufferString("a\nbb\nccc\ndddd\nééééé\n"),
+		WithASCII(true),
+		WithCapitalize("first"),
+		WithLanguage(language.French),
+		WithLengthRange(2, 4),
+		WithMinEntropy(0),
+		WithRandSource(constantReader(0)),
+	)
+	if !d.ASCII() {
+		t.Error("expected ASCII words")
+	}
+	if d.Capitalize() != "first" {
+		t.Errorf("expected first, got %s", d.Capitalize())
+	}
+	if d.Language() != language.French {
+		t.Errorf("expected %s, got %s", language.French, d.Language())
+	}
+	if d.MinWordLength() != 2 || d.MaxWordLength() != 4 {
+		t.Errorf("expected words of 2 to 4 letters, got %d to %d", d.MinWordLength(), d.MaxWordLength())
+	}
+	if d.MinEntropy() != 0 {
+		t.Errorf("expected no minimum entropy, got %0.1f", d.MinEntropy())
+	}
+	words, err := d.Passphrase(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := strings.Join(words, " "); actual != "Bb Bb" {
+		t.Errorf("expected Bb Bb, got %s", actual)
+	}
+
+	d, err = LoadDictionary("es", WithLengthRange(5, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.MinWordLength() != 5 || d.MaxWordLength() != 5 {
+		t.Errorf("expected words of 5 letters, got %d to %d", d.MinWordLength(), d.MaxWordLength())
+	}
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+	d := NewDictionary(bytes.NewBufferString("a\nbb\nccc\ndddd\n"))
+	short := d.With(WithLengthRange(0, 2), WithCapitalize("all"))
+	long := d.With(WithLengthRange(3, 0))
+
+	if d.Length() != 4 || d.Capitalize() == "all" {
+		t.Errorf("expected the original to be unchanged, got %d words and %s capitalization", d.Length(), d.Capitalize())
+	}
+	if short.Length() != 2 || short.Capitalize() != "all" {
+		t.Errorf("expected 2 words and all capitalization, got %d words and %s", short.Length(), short.Capitalize())
+	}
+	if long.Length() != 2 || long.Word(0) != "ccc" {
+		t.Errorf("expected ccc and dddd, got %d words starting with %s", long.Length(), long.Word(0))
+	}
+	if &d.words[0] != &short.words[0] || &d.words[0] != &long.words[0] {
+		t.Error("expected the word list to be shared")
+	}
+
+	// folding the copy to ASCII leaves the shared word list alone
+	ascii := NewDictionary(bytes.NewBufferString("año\nano\n"))
+	folded := ascii.With(WithASCII(true))
+	if ascii.Length() != 2 || folded.Length() != 1 {
+		t.Errorf("expected 2 and 1 words, got %d and %d", ascii.Length(), folded.Length())
+	}
+}
+
+func TestDictionaryConcurrent(t *testing.T) {
+	t.Parallel()
+	base, err := LoadDictionary("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := base.With(WithCapitalize("random"), WithRandSource(&hashReader{}))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := d.Passphrase(4); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
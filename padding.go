@@ -0,0 +1,121 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DefaultPaddingSymbols are the symbols that padding is drawn from when a
+// Padding does not set its own.
+const DefaultPaddingSymbols = "!@$%^&*-_+=:|~?/.;"
+
+// Padding adds random digits and symbols to a passphrase, in the style of
+// hsxkpasswd, for systems that require passwords to contain them. The padding
+// before the words is the symbols followed by the digits, and the padding
+// after the words is the digits followed by the symbols, e.g. !!12 correct
+// horse 34!!.
+type Padding struct {
+	DigitsBefore  int    // random digits before the words
+	DigitsAfter   int    // random digits after the words
+	SymbolsBefore int    // random symbols before the digits before the words
+	SymbolsAfter  int    // random symbols after the digits after the words
+	Symbols       string // the symbols to choose from, DefaultPaddingSymbols if empty
+	// Placement is where the padding goes. The around placement puts it
+	// before the first word and after the last, and the between placement
+	// puts it after the first word and before the last, so that passphrases
+	// of more than one word start and end with a word. If the placement is
+	// not recognized, then 'around' is used.
+	Placement string
+}
+
+// normalize returns pad with negative counts set to 0, a recognized
+// placement, and each of its symbols only once.
+func (pad Padding) normalize() Padding {
+	for _, n := range []*int{&pad.DigitsBefore, &pad.DigitsAfter, &pad.SymbolsBefore, &pad.SymbolsAfter} {
+		if *n < 0 {
+			*n = 0
+		}
+	}
+	if pad.Placement != "between" {
+		pad.Placement = "around"
+	}
+	pad.Symbols = uniqueRunes(pad.Symbols)
+	return pad
+}
+
+// gaps returns the number of separators in an n-word passphrase with the
+// padding.
+func (pad Padding) gaps(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	gaps := n - 1
+	if pad.DigitsBefore+pad.SymbolsBefore > 0 {
+		gaps++
+	}
+	if pad.DigitsAfter+pad.SymbolsAfter > 0 {
+		gaps++
+	}
+	return gaps
+}
+
+// symbols returns the symbols that padding is drawn from.
+func (pad Padding) symbols() []rune {
+	if pad.Symbols == "" {
+		return []rune(DefaultPaddingSymbols)
+	}
+	return []rune(pad.Symbols)
+}
+
+// entropy returns the bits of entropy of the digits, and of the symbols, of
+// the padding.
+func (pad Padding) entropy() (float64, float64) {
+	digits := float64(pad.DigitsBefore+pad.DigitsAfter) * math.Log2(10)
+	symbols := 0.0
+	if n := pad.SymbolsBefore + pad.SymbolsAfter; n > 0 {
+		symbols = float64(n) * math.Log2(float64(len(pad.symbols())))
+	}
+	return digits, symbols
+}
+
+// generate returns random padding for before and after the words.
+func (pad Padding) generate(r io.Reader) (string, string, error) {
+	var before, after strings.Builder
+	steps := []struct {
+		b     *strings.Builder
+		n     int
+		chars []rune
+	}{
+		{&before, pad.SymbolsBefore, pad.symbols()},
+		{&before, pad.DigitsBefore, []rune("0123456789")},
+		{&after, pad.DigitsAfter, []rune("0123456789")},
+		{&after, pad.SymbolsAfter, pad.symbols()},
+	}
+	for _, step := range steps {
+		for i := 0; i < step.n; i++ {
+			idx, err := rand.Int(r, big.NewInt(int64(len(step.chars))))
+			if err != nil {
+				return "", "", fmt.Errorf("cannot generate random padding: %w", err)
+			}
+			step.b.WriteRune(step.chars[idx.Int64()])
+		}
+	}
+	return before.String(), after.String(), nil
+}
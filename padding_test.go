@@ -0,0 +1,202 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPaddingNormalize(t *testing.T) {
+	t.Parallel()
+	pad := Padding{DigitsBefore: -1, DigitsAfter: 2, SymbolsAfter: -3, Symbols: "!!@!#", Placement: "middle"}.normalize()
+	expected := Padding{DigitsAfter: 2, Symbols: "!@#", Placement: "around"}
+	if pad != expected {
+		t.Errorf("expected %+v, got %+v", expected, pad)
+	}
+}
+
+func TestPaddingEntropy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pad     Padding
+		digits  float64
+		symbols float64
+	}{
+		{Padding{}, 0, 0},
+		{Padding{DigitsBefore: 2, DigitsAfter: 1}, 3 * math.Log2(10), 0},
+		{Padding{SymbolsBefore: 1, SymbolsAfter: 1, Symbols: "!@#$"}, 0, 4},
+		{Padding{SymbolsBefore: 1}, 0, math.Log2(float64(len(DefaultPaddingSymbols)))},
+		{Padding{DigitsAfter: 1, SymbolsAfter: 3, Symbols: "!"}, math.Log2(10), 0},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			t.Parallel()
+			digits, symbols := test.pad.entropy()
+			if digits != test.digits {
+				t.Errorf("expected %0.2f bits of digits, got %0.2f", test.digits, digits)
+			}
+			if symbols != test.symbols {
+				t.Errorf("expected %0.2f bits of symbols, got %0.2f", test.symbols, symbols)
+			}
+		})
+	}
+}
+
+func TestPaddingGenerate(t *testing.T) {
+	t.Parallel()
+	pad := Padding{DigitsBefore: 2, DigitsAfter: 3, SymbolsBefore: 1, SymbolsAfter: 2, Symbols: "!?"}
+	for i := 0; i < 20; i++ {
+		before, after, err := pad.generate(&hashReader{counter: uint64(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(before) != 3 || !strings.ContainsAny(before[:1], "!?") || strings.Trim(before[1:], "0123456789") != "" {
+			t.Errorf("expected a symbol and 2 digits, got %s", before)
+		}
+		if len(after) != 5 || strings.Trim(after[:3], "0123456789") != "" || strings.Trim(after[3:], "!?") != "" {
+			t.Errorf("expected 3 digits and 2 symbols, got %s", after)
+		}
+	}
+}
+
+func TestPassphraseStringPadding(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		p        Passphrase
+		expected string
+	}{
+		{Passphrase{Words: []string{"a", "b", "c"}, Separator: ".", Before: "!1", After: "2!"}, "!1.a.b.c.2!"},
+		{Passphrase{Words: []string{"a", "b", "c"}, Separator: ".", Before: "!1", After: "2!", Placement: "between"}, "a.!1.b.2!.c"},
+		{Passphrase{Words: []string{"a", "b"}, Separator: ".", Before: "!1", After: "2!", Placement: "between"}, "a.!1.2!.b"},
+		{Passphrase{Words: []string{"a"}, Separator: ".", Before: "!1", After: "2!", Placement: "between"}, "!1.a.2!"},
+		{Passphrase{Words: []string{"a", "b"}, Separator: "-", After: "42"}, "a-b-42"},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			t.Parallel()
+			if actual := test.p.String(); actual != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGeneratePadding(t *testing.T) {
+	t.Parallel()
+	pad := Padding{DigitsBefore: 2, DigitsAfter: 2, SymbolsAfter: 1, Symbols: "!@", Placement: "between"}
+	d, err := LoadDictionary("en", WithPadding(pad), WithRandSource(&hashReader{}), WithSeparator("."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := d.Generate(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Before) != 2 || len(p.After) != 3 || p.Placement != "between" {
+		t.Errorf("expected padding of 2 and 3 characters between the words, got %+v", p)
+	}
+	expected := strings.Join([]string{p.Words[0], p.Before, p.Words[1], p.After, p.Words[2]}, ".")
+	if p.String() != expected {
+		t.Errorf("expected %s, got %s", expected, p.String())
+	}
+	if p.Entropy.Digits != 4*math.Log2(10) || p.Entropy.Symbols != 1 {
+		t.Errorf("expected %0.2f bits of digits and 1 bit of symbols, got %+v", 4*math.Log2(10), p.Entropy)
+	}
+
+	// padding counts towards the entropy needed
+	n, err := d.WordsForEntropy(40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := int(math.Ceil((40 - p.Entropy.Digits - 1) / d.WordEntropy())); n != expected {
+		t.Errorf("expected %d words, got %d", expected, n)
+	}
+	if n, _ := d.WordsForEntropy(1); n != 1 {
+		t.Errorf("expected 1 word, got %d", n)
+	}
+
+	// padding alone is not a passphrase
+	for _, n := range []int{0, -1} {
+		if _, err := d.Generate(n); !errors.Is(err, ErrInvalidWordCount) {
+			t.Errorf("expected ErrInvalidWordCount for %d words, got %v", n, err)
+		}
+	}
+}
+
+func TestMultiDictionaryPadding(t *testing.T) {
+	t.Parallel()
+	en, err := LoadDictionary("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	es, err := LoadDictionary("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMultiDictionary(en, es)
+	m.SetPadding(Padding{DigitsAfter: 3})
+	p, err := m.Generate(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.After) != 3 || !strings.HasSuffix(p.String(), " "+p.After) {
+		t.Errorf("expected 3 digits after the words, got %s", p.String())
+	}
+	if expected := 3 * math.Log2(10); p.Entropy.Digits != expected {
+		t.Errorf("expected %0.2f bits of digits, got %0.2f", expected, p.Entropy.Digits)
+	}
+	for _, n := range []int{0, -1} {
+		if _, err := m.Generate(n); !errors.Is(err, ErrInvalidWordCount) {
+			t.Errorf("expected ErrInvalidWordCount for %d words, got %v", n, err)
+		}
+	}
+}
+
+func TestWordsForEntropyPaddingOnly(t *testing.T) {
+	t.Parallel()
+	d := newDictionary([]string{"only"})
+	d.SetPadding(Padding{DigitsAfter: 20})
+	if n, err := d.WordsForEntropy(30); err != nil || n != 1 {
+		t.Errorf("expected 1 word, got %d and %v", n, err)
+	}
+	if n, err := d.WordsForEntropy(80); err == nil {
+		t.Errorf("expected an error, got %d words", n)
+	}
+
+	// without padding, the separator of the phrase policy needs two words
+	d = newDictionary([]string{"only"})
+	d.SetSeparatorSet("-_.=")
+	d.SetSeparatorPolicy("phrase")
+	if n, err := d.WordsForEntropy(2); err != nil || n != 2 {
+		t.Errorf("expected 2 words, got %d and %v", n, err)
+	}
+	if n, err := d.WordsForEntropy(3); err == nil {
+		t.Errorf("expected an error, got %d words", n)
+	}
+
+	m := NewMultiDictionary(newDictionary([]string{"a"}), newDictionary([]string{"b"}))
+	m.SetPadding(Padding{DigitsAfter: 20})
+	if n, err := m.WordsForEntropy(30); err != nil || n != 1 {
+		t.Errorf("expected 1 word, got %d and %v", n, err)
+	}
+	if n, err := m.WordsForEntropy(80); err == nil {
+		t.Errorf("expected an error, got %d words", n)
+	}
+}
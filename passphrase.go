@@ -0,0 +1,143 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Passphrase is a generated passphrase, along with how it was generated.
+type Passphrase struct {
+	Words        []string     // the words, as capitalized
+	Indexes      []int        // the index of each word in its dictionary
+	Dictionaries []int        // the index of the dictionary of each word, for a MultiDictionary
+	Separator    string       // the separator between words, if there is only one
+	Separators   []string     // the separator of each gap, if chosen at random for each
+	Before       string       // the padding before the words, if any
+	After        string       // the padding after the words, if any
+	Placement    string       // where the padding goes, see Padding
+	Language     language.Tag // the language of the words, or language.Und if mixed
+	Entropy      Entropy      // the entropy of the passphrase
+	Settings     Settings     // the settings the passphrase was generated with
+}
+
+// Settings are the dictionary settings a passphrase was generated with.
+type Settings struct {
+	ASCII         bool    `json:"ascii"`
+	Capitalize    string  `json:"capitalize"`
+	MinWordLength int     `json:"minLength"`
+	MaxWordLength int     `json:"maxLength"`
+	MinEntropy    float64 `json:"minEntropy"`
+}
+
+// common returns the settings that s and other agree on, with the others set
+// to their zero values.
+func (s Settings) common(other Settings) Settings {
+	if s.ASCII != other.ASCII {
+		s.ASCII = false
+	}
+	if s.Capitalize != other.Capitalize {
+		s.Capitalize = ""
+	}
+	if s.MinWordLength != other.MinWordLength {
+		s.MinWordLength = 0
+	}
+	if s.MaxWordLength != other.MaxWordLength {
+		s.MaxWordLength = 0
+	}
+	if s.MinEntropy != other.MinEntropy {
+		s.MinEntropy = 0
+	}
+	return s
+}
+
+// String returns the words and padding joined by the separators.
+func (p Passphrase) String() string {
+	parts := p.parts()
+	if len(p.Separators) == 0 {
+		return strings.Join(parts, p.Separator)
+	}
+	var b strings.Builder
+	for i, part := range parts {
+		switch {
+		case i == 0:
+		case i <= len(p.Separators):
+			b.WriteString(p.Separators[i-1])
+		default:
+			b.WriteString(p.Separator)
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// parts returns the words and padding of p, in order.
+func (p Passphrase) parts() []string {
+	if len(p.Words) == 0 || p.Before == "" && p.After == "" {
+		return p.Words
+	}
+	// the between placement keeps the first and last words at the ends
+	first, last := 0, len(p.Words)
+	if p.Placement == "between" && len(p.Words) > 1 {
+		first, last = 1, len(p.Words)-1
+	}
+	parts := make([]string, 0, len(p.Words)+2)
+	parts = append(parts, p.Words[:first]...)
+	if p.Before != "" {
+		parts = append(parts, p.Before)
+	}
+	parts = append(parts, p.Words[first:last]...)
+	if p.After != "" {
+		parts = append(parts, p.After)
+	}
+	return append(parts, p.Words[last:]...)
+}
+
+// MarshalJSON returns the JSON encoding of p, which includes the passphrase
+// as a string and its total bits of entropy. Its keys are camel case, like
+// those of Settings.
+func (p Passphrase) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Phrase       string   `json:"phrase"`
+		Words        []string `json:"words"`
+		WordCount    int      `json:"wordCount"`
+		Indexes      []int    `json:"indexes"`
+		Dictionaries []int    `json:"dictionaries,omitempty"`
+		Separator    string   `json:"separator"`
+		Separators   []string `json:"separators,omitempty"`
+		Before       string   `json:"before,omitempty"`
+		After        string   `json:"after,omitempty"`
+		Placement    string   `json:"placement,omitempty"`
+		Language     string   `json:"language"`
+		Entropy      float64  `json:"entropy"`
+		Settings     Settings `json:"settings"`
+	}{
+		Phrase:       p.String(),
+		Words:        p.Words,
+		WordCount:    len(p.Words),
+		Indexes:      p.Indexes,
+		Dictionaries: p.Dictionaries,
+		Separator:    p.Separator,
+		Separators:   p.Separators,
+		Before:       p.Before,
+		After:        p.After,
+		Placement:    p.Placement,
+		Language:     p.Language.String(),
+		Entropy:      p.Entropy.Bits(),
+		Settings:     p.Settings,
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestPassphraseString(t *testing.T) {
+	t.Parallel()
+	p := Passphrase{Words: []string{"correct", "horse", "battery", "staple"}, Separator: "-"}
+	if actual := p.String(); actual != "correct-horse-battery-staple" {
+		t.Errorf("expected correct-horse-battery-staple, got %s", actual)
+	}
+	if actual := (Passphrase{}).String(); actual != "" {
+		t.Errorf("expected an empty string, got %s", actual)
+	}
+}
+
+func TestPassphraseMarshalJSON(t *testing.T) {
+	t.Parallel()
+	p := Passphrase{
+		Words:     []string{"Correct", "Horse"},
+		Indexes:   []int{12, 345},
+		Separator: ".",
+		Language:  language.English,
+		Entropy:   Entropy{Words: 20, Capitalization: 2.5},
+		Settings:  Settings{Capitalize: "first", MaxWordLength: 7, MinEntropy: 20},
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"phrase":"Correct.Horse","words":["Correct","Horse"],"wordCount":2,"indexes":[12,345],"separator":".",` +
+		`"language":"en","entropy":22.5,"settings":{"ascii":false,"capitalize":"first","minLength":0,"maxLength":7,"minEntropy":20}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	p.Dictionaries = []int{0, 1}
+	p.Before, p.After, p.Placement = "!1", "2!", "between"
+	data, err = json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `{"phrase":"Correct.!1.2!.Horse","words":["Correct","Horse"],"wordCount":2,"indexes":[12,345],"dictionaries":[0,1],` +
+		`"separator":".","before":"!1","after":"2!","placement":"between",` +
+		`"language":"en","entropy":22.5,"settings":{"ascii":false,"capitalize":"first","minLength":0,"maxLength":7,"minEntropy":20}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	d, err := LoadDictionary("es",
+		WithCapitalize("all"),
+		WithLengthRange(4, 8),
+		WithRandSource(&hashReader{}),
+		WithSeparator("_"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := d.Generate(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Words) != 5 || len(p.Indexes) != 5 {
+		t.Fatalf("expected 5 words and indexes, got %v and %v", p.Words, p.Indexes)
+	}
+	for i, idx := range p.Indexes {
+		if expected := strings.ToUpper(d.Word(idx)); p.Words[i] != expected {
+			t.Errorf("expected word %d to be %s, got %s", i, expected, p.Words[i])
+		}
+	}
+	if actual := p.String(); actual != strings.Join(p.Words, "_") {
+		t.Errorf("expected words separated by _, got %s", actual)
+	}
+	if p.Language != language.Spanish {
+		t.Errorf("expected %s, got %s", language.Spanish, p.Language)
+	}
+	if p.Entropy != d.PassphraseEntropy(5) {
+		t.Errorf("expected %v, got %v", d.PassphraseEntropy(5), p.Entropy)
+	}
+	expected := Settings{Capitalize: "all", MinWordLength: 4, MaxWordLength: 8, MinEntropy: DefaultMinEntropy}
+	if p.Settings != expected {
+		t.Errorf("expected %+v, got %+v", expected, p.Settings)
+	}
+
+	var entropyErr *EntropyError
+	p, err = d.Generate(1)
+	if !errors.As(err, &entropyErr) {
+		t.Fatalf("expected an EntropyError, got %v", err)
+	}
+	if p.Words != nil || p.Entropy.Bits() != entropyErr.Achieved {
+		t.Errorf("expected no words and %0.1f bits, got %v and %0.1f", entropyErr.Achieved, p.Words, p.Entropy.Bits())
+	}
+}
+
+func TestMultiDictionaryGenerate(t *testing.T) {
+	t.Parallel()
+	en, err := LoadDictionary("en", WithCapitalize("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	es, err := LoadDictionary("es", WithCapitalize("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMultiDictionary(en, es)
+	m.SetSeparator("-")
+	p, err := m.Generate(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Words) != 4 || len(p.Indexes) != 4 {
+		t.Fatalf("expected 4 words and indexes, got %v and %v", p.Words, p.Indexes)
+	}
+	if strings.Count(p.String(), "-") != 3 {
+		t.Errorf("expected words separated by -, got %s", p.String())
+	}
+	if p.Language != language.Und {
+		t.Errorf("expected %s, got %s", language.Und, p.Language)
+	}
+	if p.Settings.Capitalize != "first" {
+		t.Errorf("expected first, got %s", p.Settings.Capitalize)
+	}
+
+	// settings that differ are left out
+	m = NewMultiDictionary(en.With(WithLengthRange(3, 8)), es.With(WithCapitalize("all"), WithLengthRange(3, 9)))
+	expected := Settings{MinWordLength: 3, MinEntropy: DefaultMinEntropy}
+	if p, _ := m.Generate(4); p.Settings != expected {
+		t.Errorf("expected %+v, got %+v", expected, p.Settings)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/wfscheper/xkcdpwd/internal/langs"
+	"golang.org/x/text/language"
+)
+
+// Registry holds the word lists that dictionaries can be loaded from: those in
+// the user's languages directory, those in the system-wide languages
+// directories, and those embedded in xkcdpwd, in order of precedence.
+type Registry struct {
+	r *langs.Registry
+}
+
+// NewRegistry returns a Registry of the word lists in the languages
+// directories found in the environment read by getenv, such as os.Getenv, and
+// of the embedded word lists. If the word lists cannot be listed, then the
+// returned error wraps ErrUnreadableWordlist.
+func NewRegistry(getenv func(string) string) (*Registry, error) {
+	r, err := langs.NewRegistry(langs.DefaultSources(getenv)...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnreadableWordlist, err)
+	}
+	return &Registry{r}, nil
+}
+
+// LoadDictionary is like the LoadDictionary function, but loads the word lists
+// of r.
+func (r *Registry) LoadDictionary(lang string, opts ...Option) (*Dictionary, error) {
+	tag, data, err := r.r.GetLanguage(lang)
+	if err != nil {
+		if errors.Is(err, ErrUnknownLanguage) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnreadableWordlist, err)
+	}
+	d, err := ReadDictionary(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	d.SetLanguage(tag)
+	d.apply(opts)
+	return d, nil
+}
+
+// Languages returns the language tags of the word lists in r, with the
+// default language first.
+func (r *Registry) Languages() []language.Tag {
+	return r.r.Tags()
+}
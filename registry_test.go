@@ -0,0 +1,60 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wfscheper/xkcdpwd/internal/userinfo"
+	"golang.org/x/text/language"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	home := t.TempDir()
+	env := map[string]string{"HOME": home, "XDG_CONFIG_HOME": home, "AppData": home}
+	getenv := func(key string) string { return env[key] }
+	dir, err := userinfo.DefaultLanguagesDir("xkcdpwd", getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "la"), []byte("unus\nduo\ntres\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRegistry(getenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := r.LoadDictionary("la", WithCapitalize("all"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Length() != 3 || d.Language() != language.MustParse("la") || d.Capitalize() != "all" {
+		t.Errorf("expected 3 capitalized Latin words, got %d %s words capitalized %s", d.Length(), d.Language(), d.Capitalize())
+	}
+	tags := r.Languages()
+	if tags[0] != language.English {
+		t.Errorf("expected the default language first, got %v", tags)
+	}
+	if _, err := r.LoadDictionary("zz"); !errors.Is(err, ErrUnknownLanguage) {
+		t.Errorf("expected ErrUnknownLanguage, got %v", err)
+	}
+}
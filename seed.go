@@ -0,0 +1,90 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+// maxSeededRead is the most bytes an HMAC-DRBG may generate in one request.
+const maxSeededRead = 1 << 16
+
+// SeededReader is a deterministic stream of random looking bytes, generated
+// by HMAC-DRBG with SHA-256 (NIST SP 800-90A) from a seed. The same seed
+// always produces the same bytes, which makes passphrases reproducible in
+// tests and examples.
+//
+// Passphrases generated from a SeededReader are only as secret as its seed,
+// so never use them as real secrets.
+type SeededReader struct {
+	k []byte
+	v []byte
+}
+
+// NewSeededReader returns a SeededReader of seed.
+func NewSeededReader(seed []byte) *SeededReader {
+	s := &SeededReader{
+		k: make([]byte, sha256.Size),
+		v: make([]byte, sha256.Size),
+	}
+	for i := range s.v {
+		s.v[i] = 0x01
+	}
+	s.update(seed)
+	return s
+}
+
+// Read fills p with the next bytes of the stream. It never returns an error.
+func (s *SeededReader) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); n += maxSeededRead {
+		end := n + maxSeededRead
+		if end > len(p) {
+			end = len(p)
+		}
+		s.generate(p[n:end])
+	}
+	return len(p), nil
+}
+
+// generate fills p with one request's worth of bytes.
+func (s *SeededReader) generate(p []byte) {
+	mac := hmac.New(sha256.New, s.k)
+	for n := 0; n < len(p); {
+		s.v = sum(mac, s.v)
+		n += copy(p[n:], s.v)
+	}
+	s.update(nil)
+}
+
+// update mixes data into the state of the generator.
+func (s *SeededReader) update(data []byte) {
+	s.k = sum(hmac.New(sha256.New, s.k), s.v, []byte{0x00}, data)
+	s.v = sum(hmac.New(sha256.New, s.k), s.v)
+	if len(data) == 0 {
+		return
+	}
+	s.k = sum(hmac.New(sha256.New, s.k), s.v, []byte{0x01}, data)
+	s.v = sum(hmac.New(sha256.New, s.k), s.v)
+}
+
+// sum returns the HMAC of the concatenation of data.
+func sum(mac hash.Hash, data ...[]byte) []byte {
+	mac.Reset()
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
@@ -0,0 +1,67 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestSeededReader(t *testing.T) {
+	t.Parallel()
+	// the first HMAC_DRBG SHA-256 test vector of NIST's CAVP, without
+	// prediction resistance, reseeding or additional input
+	seed, _ := hex.DecodeString("ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488" +
+		"659ba96c601dc69fc902940805ec0ca8")
+	expected, _ := hex.DecodeString("e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89" +
+		"d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc1" +
+		"07694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668" +
+		"961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8")
+	s := NewSeededReader(seed)
+	actual := make([]byte, len(expected))
+	for i := 0; i < 2; i++ {
+		if _, err := s.Read(actual); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("expected %x, got %x", expected, actual)
+	}
+}
+
+func TestSeededReaderPassphrase(t *testing.T) {
+	t.Parallel()
+	passphrase := func(seed string) string {
+		d, err := LoadDictionary("en")
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.SetCapitalize("random")
+		d.SetRandSource(NewSeededReader([]byte(seed)))
+		words, err := d.Passphrase(6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Join(words, " ")
+	}
+	a, b := passphrase("correct horse"), passphrase("correct horse")
+	if a != b {
+		t.Errorf("expected the same passphrase from the same seed, got '%s' and '%s'", a, b)
+	}
+	if c := passphrase("battery staple"); a == c {
+		t.Errorf("expected different passphrases from different seeds, got '%s' twice", a)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// randomSeparators chooses the separators of a passphrase at random from a set
+// of characters, instead of using a fixed separator.
+type randomSeparators struct {
+	set    string // the characters to choose from, none means a fixed separator
+	policy string // gap or phrase
+}
+
+// newRandomSeparators returns randomSeparators for set and policy, with each
+// character of set only once. If policy is not recognized, then 'gap' is
+// used.
+func newRandomSeparators(set, policy string) randomSeparators {
+	if policy != "phrase" {
+		policy = "gap"
+	}
+	return randomSeparators{set: uniqueRunes(set), policy: policy}
+}
+
+// bits returns the bits of entropy of choosing one separator.
+func (s randomSeparators) bits() float64 {
+	if n := len([]rune(s.set)); n > 1 {
+		return math.Log2(float64(n))
+	}
+	return 0
+}
+
+// entropy returns the bits of entropy the separators add to a passphrase with
+// gaps gaps between its words and padding.
+func (s randomSeparators) entropy(gaps int) float64 {
+	switch {
+	case gaps <= 0:
+		return 0
+	case s.policy == "phrase":
+		return s.bits()
+	default:
+		return float64(gaps) * s.bits()
+	}
+}
+
+// choose sets the separators of p at random, if there is a set to choose
+// from. The phrase policy chooses one separator for the whole passphrase, and
+// the gap policy chooses one for each gap.
+func (s randomSeparators) choose(p *Passphrase, r io.Reader) error {
+	if s.set == "" {
+		return nil
+	}
+	set := []rune(s.set)
+	n := 1
+	if s.policy == "gap" {
+		n = len(p.parts()) - 1
+	}
+	separators := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		idx, err := rand.Int(r, big.NewInt(int64(len(set))))
+		if err != nil {
+			return fmt.Errorf("cannot generate random separators: %w", err)
+		}
+		separators = append(separators, string(set[idx.Int64()]))
+	}
+	if s.policy == "phrase" {
+		p.Separator = separators[0]
+		return nil
+	}
+	p.Separator, p.Separators = "", separators
+	return nil
+}
+
+// uniqueRunes returns s with each character only once, in the same order.
+func uniqueRunes(s string) string {
+	seen := map[rune]bool{}
+	var b strings.Builder
+	for _, r := range s {
+		if !seen[r] {
+			seen[r] = true
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,197 @@
+// Copyright © 2023 Walter Scheper <walter.scheper@gmal.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xkcdpwd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRandomSeparatorsEntropy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		set      string
+		policy   string
+		gaps     int
+		expected float64
+	}{
+		{"", "gap", 3, 0},
+		{"-", "gap", 3, 0},
+		{"-_.=", "gap", 3, 6},
+		{"-_.=", "gap", 0, 0},
+		{"-_.=", "phrase", 3, 2},
+		{"-_.=", "phrase", 0, 0},
+		{"--__", "gap", 3, 3},
+		{"-_.=", "foo", 1, 2},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			t.Parallel()
+			if actual := newRandomSeparators(test.set, test.policy).entropy(test.gaps); actual != test.expected {
+				t.Errorf("expected %0.1f bits, got %0.1f", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPassphraseStringSeparators(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		p        Passphrase
+		expected string
+	}{
+		{Passphrase{Words: []string{"a", "b", "c"}, Separators: []string{"-", "_"}}, "a-b_c"},
+		{Passphrase{Words: []string{"a", "b"}, Separators: []string{"=", "+", "."}, Before: "1", After: "2"}, "1=a+b.2"},
+		{Passphrase{Words: []string{"a", "b", "c"}, Separator: " ", Separators: []string{"-"}}, "a-b c"},
+	}
+	for idx, test := range tests {
+		test := test
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			t.Parallel()
+			if actual := test.p.String(); actual != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGenerateSeparatorSet(t *testing.T) {
+	t.Parallel()
+	d, err := LoadDictionary("en", WithSeparatorSet("-_.=+", "gap"), WithRandSource(&hashReader{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.SeparatorSet() != "-_.=+" || d.SeparatorPolicy() != "gap" {
+		t.Errorf("expected -_.=+ and gap, got %s and %s", d.SeparatorSet(), d.SeparatorPolicy())
+	}
+	p, err := d.Generate(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Separators) != 3 || p.Separator != "" {
+		t.Fatalf("expected 3 separators, got %q and %q", p.Separators, p.Separator)
+	}
+	var b strings.Builder
+	for i, w := range p.Words {
+		if i > 0 {
+			if !strings.Contains("-_.=+", p.Separators[i-1]) {
+				t.Errorf("expected a separator from -_.=+, got %s", p.Separators[i-1])
+			}
+			b.WriteString(p.Separators[i-1])
+		}
+		b.WriteString(w)
+	}
+	if p.String() != b.String() {
+		t.Errorf("expected %s, got %s", b.String(), p.String())
+	}
+	if expected := 3 * math.Log2(5); p.Entropy.Separators != expected {
+		t.Errorf("expected %0.2f bits, got %0.2f", expected, p.Entropy.Separators)
+	}
+
+	// padding adds gaps
+	p, err = d.With(WithPadding(Padding{DigitsBefore: 1, DigitsAfter: 1})).Generate(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Separators) != 5 {
+		t.Errorf("expected 5 separators, got %q", p.Separators)
+	}
+
+	d.SetSeparatorPolicy("phrase")
+	p, err = d.Generate(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Separators != nil || len(p.Separator) != 1 || !strings.Contains("-_.=+", p.Separator) {
+		t.Errorf("expected one separator from -_.=+, got %q and %q", p.Separators, p.Separator)
+	}
+	if p.String() != strings.Join(p.Words, p.Separator) {
+		t.Errorf("expected words joined by %s, got %s", p.Separator, p.String())
+	}
+	if expected := math.Log2(5); p.Entropy.Separators != expected {
+		t.Errorf("expected %0.2f bits, got %0.2f", expected, p.Entropy.Separators)
+	}
+}
+
+func TestWordsForEntropySeparatorSet(t *testing.T) {
+	t.Parallel()
+	d, err := LoadDictionary("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dicts := []*Dictionary{
+		d.With(WithSeparatorSet("-_.=+", "gap")),
+		d.With(WithSeparatorSet("-_.=+", "phrase")),
+		d.With(WithSeparatorSet("!@#$%^&*()", "gap"), WithPadding(Padding{DigitsAfter: 2, SymbolsBefore: 1})),
+	}
+	for idx, d := range dicts {
+		d := d
+		t.Run(fmt.Sprint(idx+1), func(t *testing.T) {
+			t.Parallel()
+			for _, bits := range []float64{1, 30, 52.4, 80, 128, 1000} {
+				n, err := d.WordsForEntropy(bits)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d.Entropy(n) < bits || n > 1 && d.Entropy(n-1) >= bits {
+					t.Errorf("expected the fewest words for %0.1f bits, got %d", bits, n)
+				}
+			}
+		})
+	}
+}
+
+func TestWordsForEntropySeparatorSetEmpty(t *testing.T) {
+	t.Parallel()
+	// the separators alone must not be mistaken for entropy per word
+	d := newDictionary([]string{"a", "b"})
+	d.SetMinWordLength(2)
+	d.SetSeparatorSet("-_")
+	if n, err := d.WordsForEntropy(80); !errors.Is(err, ErrEmptyWordlist) {
+		t.Errorf("expected ErrEmptyWordlist, got %d words and %v", n, err)
+	}
+	m := NewMultiDictionary(newDictionary([]string{"a", "b"}), d)
+	m.SetSeparatorSet("-_")
+	if n, err := m.WordsForEntropy(80); !errors.Is(err, ErrEmptyWordlist) {
+		t.Errorf("expected ErrEmptyWordlist, got %d words and %v", n, err)
+	}
+}
+
+func TestMultiDictionarySeparatorSet(t *testing.T) {
+	t.Parallel()
+	en, err := LoadDictionary("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	es, err := LoadDictionary("es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMultiDictionary(en, es)
+	m.SetSeparatorSet("-_")
+	p, err := m.Generate(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Separators) != 2 {
+		t.Errorf("expected 2 separators, got %q", p.Separators)
+	}
+	if p.Entropy.Separators != 2 {
+		t.Errorf("expected 2 bits, got %0.2f", p.Entropy.Separators)
+	}
+}